@@ -0,0 +1,109 @@
+// Command mercury-gen generates typed Go emitters and listeners from Mercury
+// event contracts.
+//
+//	mercury-gen -package events -out events/events.go contract.json
+//	mercury-gen -package events -out events/events.go -host http://127.0.0.1:8081
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/codegen"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "mercury-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	packageName := flag.String("package", "events", "package name of the generated file")
+	out := flag.String("out", "", "file to write, stdout when empty")
+	host := flag.String("host", "", "Mercury host to fetch contracts from with get-event-contracts")
+	skillId := flag.String("skill-id", "", "skill id to authenticate with when fetching")
+	apiKey := flag.String("api-key", "", "skill api key to authenticate with when fetching")
+	namespace := flag.String("namespace", "", "only generate events whose fqen starts with this namespace")
+	flag.Parse()
+
+	contracts, err := loadContracts(*host, *skillId, *apiKey, flag.Args())
+	if err != nil {
+		return err
+	}
+
+	if *namespace != "" {
+		contracts = filterNamespace(contracts, *namespace)
+	}
+
+	source, err := codegen.Generate(contracts, codegen.Options{PackageName: *packageName})
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+
+	return os.WriteFile(*out, source, 0o644)
+}
+
+func loadContracts(host string, skillId string, apiKey string, paths []string) ([]*mercury.EventContract, error) {
+	if host == "" && len(paths) == 0 {
+		return nil, fmt.Errorf("pass contract files or -host")
+	}
+
+	var contracts []*mercury.EventContract
+	for _, path := range paths {
+		contract, err := codegen.LoadContract(path)
+		if err != nil {
+			return nil, err
+		}
+		contracts = append(contracts, contract)
+	}
+
+	if host == "" {
+		return contracts, nil
+	}
+
+	client, err := mercury.NewMercuryClient(mercury.MercuryClientOptions{Host: host})
+	if err != nil {
+		return nil, err
+	}
+	defer client.Disconnect()
+
+	if skillId != "" {
+		if _, err := client.Authenticate(mercury.AuthenticatePayload{SkillId: skillId, ApiKey: apiKey}); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	fetched, err := codegen.FetchContracts(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(contracts, fetched...), nil
+}
+
+func filterNamespace(contracts []*mercury.EventContract, namespace string) []*mercury.EventContract {
+	filtered := &mercury.EventContract{EventSignatures: map[string]mercury.EventSignature{}}
+	for _, contract := range contracts {
+		for fqen, signature := range contract.EventSignatures {
+			if strings.HasPrefix(fqen, namespace+".") {
+				filtered.EventSignatures[fqen] = signature
+			}
+		}
+	}
+	return []*mercury.EventContract{filtered}
+}
@@ -0,0 +1,215 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+)
+
+type (
+	Options struct {
+		PackageName string
+	}
+
+	typeDecl struct {
+		Name   string
+		Fields []fieldDecl
+	}
+
+	fieldDecl struct {
+		Name string
+		Type string
+		Tag  string
+	}
+
+	generator struct {
+		types []typeDecl
+		seen  map[string]bool
+	}
+)
+
+// Generate writes Go source with typed targets, payloads, responses, emitters
+// and listeners for every event in contracts.
+func Generate(contracts []*mercury.EventContract, opts Options) ([]byte, error) {
+	packageName := opts.PackageName
+	if packageName == "" {
+		packageName = "events"
+	}
+
+	signatures := map[string]mercury.EventSignature{}
+	for _, contract := range contracts {
+		for fqen, signature := range contract.EventSignatures {
+			signatures[fqen] = signature
+		}
+	}
+
+	fqens := make([]string, 0, len(signatures))
+	for fqen := range signatures {
+		fqens = append(fqens, fqen)
+	}
+	sort.Strings(fqens)
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by mercury-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", packageName)
+	fmt.Fprintf(&out, "import (\n\t\"context\"\n\n\t\"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury\"\n)\n\n")
+
+	for _, fqen := range fqens {
+		gen := &generator{seen: map[string]bool{}}
+		if err := gen.writeEvent(&out, fqen, signatures[fqen]); err != nil {
+			return nil, err
+		}
+	}
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	return formatted, nil
+}
+
+func (g *generator) writeEvent(out *bytes.Buffer, fqen string, signature mercury.EventSignature) error {
+	base := TypeName(fqen)
+	if base == "" {
+		return fmt.Errorf("cannot build a type name for '%s'", fqen)
+	}
+
+	target := base + "Target"
+	payload := base + "Payload"
+	response := base + "Response"
+
+	g.addSchema(target, signature.EmitPayloadSchema.SubSchema("target"), base)
+	g.addSchema(payload, signature.EmitPayloadSchema.SubSchema("payload"), base)
+	g.addSchema(response, signature.ResponsePayloadSchema, base)
+
+	fmt.Fprintf(out, "const %sFqen = %q\n\n", base, fqen)
+
+	for _, decl := range g.types {
+		fmt.Fprintf(out, "type %s struct {\n", decl.Name)
+		for _, field := range decl.Fields {
+			fmt.Fprintf(out, "\t%s %s `%s`\n", field.Name, field.Type, field.Tag)
+		}
+		fmt.Fprintf(out, "}\n\n")
+	}
+
+	fmt.Fprintf(out, "// Emit%s emits %s and decodes every response.\n", base, fqen)
+	fmt.Fprintf(out, "func Emit%s(ctx context.Context, client mercury.MercuryClient, target %s, payload %s) ([]%s, error) {\n", base, target, payload, response)
+	fmt.Fprintf(out, "\treturn mercury.EmitTypedContext[%s, %s, %s](ctx, client, %sFqen, target, payload)\n}\n\n", target, payload, response, base)
+
+	fmt.Fprintf(out, "// On%s listens to %s with a typed listener.\n", base, fqen)
+	fmt.Fprintf(out, "func On%s(client mercury.MercuryClient, listener mercury.TypedListener[%s, %s, %s]) (*mercury.Subscription, error) {\n", base, target, payload, response)
+	fmt.Fprintf(out, "\treturn mercury.OnTyped(client, %sFqen, listener)\n}\n\n", base)
+
+	return nil
+}
+
+func (g *generator) addSchema(name string, schema *mercury.Schema, prefix string) {
+	if g.seen[name] {
+		return
+	}
+	g.seen[name] = true
+
+	decl := typeDecl{Name: name}
+	index := len(g.types)
+	g.types = append(g.types, decl)
+
+	if schema == nil {
+		return
+	}
+
+	keys := make([]string, 0, len(schema.Fields))
+	for key := range schema.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		definition := schema.Fields[key]
+		decl.Fields = append(decl.Fields, fieldDecl{
+			Name: FieldName(key),
+			Type: g.goType(definition, prefix),
+			Tag:  jsonTag(key, definition.IsRequired),
+		})
+	}
+
+	g.types[index] = decl
+}
+
+func (g *generator) goType(definition mercury.FieldDefinition, prefix string) string {
+	goType := "any"
+
+	switch definition.Type {
+	case "id", "text", "phone", "email", "select", "password", "schemaId":
+		goType = "string"
+	case "number":
+		goType = "float64"
+	case "boolean":
+		goType = "bool"
+	case "date", "dateTime":
+		goType = "int64"
+	case "address", "image", "file", "duration", "directive":
+		goType = "map[string]any"
+	case "schema":
+		if definition.Options != nil && definition.Options.Schema != nil {
+			nested := prefix + TypeName(definition.Options.Schema.Id)
+			g.addSchema(nested, definition.Options.Schema, prefix)
+			goType = nested
+			if !definition.IsRequired && !definition.IsArray {
+				goType = "*" + nested
+			}
+		} else {
+			goType = "map[string]any"
+		}
+	}
+
+	if definition.IsArray {
+		return "[]" + goType
+	}
+
+	return goType
+}
+
+func jsonTag(key string, isRequired bool) string {
+	if isRequired {
+		return fmt.Sprintf(`json:"%s"`, key)
+	}
+	return fmt.Sprintf(`json:"%s,omitempty"`, key)
+}
+
+// TypeName turns an fqen or schema id into an exported Go name, so
+// "ns.will-send-vip::v2020_12_25" becomes "NsWillSendVipV20201225".
+func TypeName(value string) string {
+	name, version, _ := strings.Cut(value, "::")
+	return pascalCase(name) + pascalCase(version)
+}
+
+// FieldName turns a schema field key into an exported Go field name.
+func FieldName(key string) string {
+	return pascalCase(key)
+}
+
+func pascalCase(value string) string {
+	words := strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var out strings.Builder
+	for _, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		out.WriteString(string(runes))
+	}
+
+	name := out.String()
+	if name != "" && unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+
+	return name
+}
@@ -0,0 +1,194 @@
+package codegen_test
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/codegen"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+
+	t.Run("builds type names from fqens", func(t *testing.T) {
+		require.Equal(t, "WillSendVipV1", codegen.TypeName("will-send-vip::v1"))
+		require.Equal(t, "AcmeWillSendVipV20201225", codegen.TypeName("acme.will-send-vip::v2020_12_25"))
+		require.Equal(t, "OrganizationId", codegen.FieldName("organizationId"))
+	})
+
+	t.Run("generates structs and wrappers for each event", func(t *testing.T) {
+		contract, err := mercury.ParseEventContract(testkit.GenerateWillSendVipEventSignature("acme"))
+		require.NoError(t, err)
+
+		source, err := codegen.Generate([]*mercury.EventContract{contract}, codegen.Options{PackageName: "acme"})
+		require.NoError(t, err)
+
+		generated := string(source)
+		require.Contains(t, generated, "package acme")
+		require.Contains(t, generated, `const AcmeWillSendVipV1Fqen = "acme.will-send-vip::v1"`)
+		require.Contains(t, generated, "type AcmeWillSendVipV1Target struct {\n\tOrganizationId string `json:\"organizationId,omitempty\"`\n}")
+		require.Contains(t, generated, "type AcmeWillSendVipV1Payload struct {\n\tMessage string `json:\"message,omitempty\"`\n}")
+		require.Contains(t, generated, "type AcmeWillSendVipV1Response struct {\n\tMessages []string `json:\"messages\"`\n}")
+		require.Contains(t, generated, "func EmitAcmeWillSendVipV1(ctx context.Context, client mercury.MercuryClient, target AcmeWillSendVipV1Target, payload AcmeWillSendVipV1Payload) ([]AcmeWillSendVipV1Response, error)")
+		require.Contains(t, generated, "func OnAcmeWillSendVipV1(client mercury.MercuryClient, listener mercury.TypedListener[AcmeWillSendVipV1Target, AcmeWillSendVipV1Payload, AcmeWillSendVipV1Response]) (*mercury.Subscription, error)")
+
+		requireCompiles(t, source)
+	})
+
+	t.Run("generates nested schemas as their own structs", func(t *testing.T) {
+		contract, err := mercury.ParseEventContract(map[string]any{
+			"eventSignatures": map[string]any{
+				"get-location::v1": map[string]any{
+					"responsePayloadSchema": map[string]any{
+						"id": "getLocationResponse",
+						"fields": map[string]any{
+							"location": map[string]any{
+								"type": "schema",
+								"options": map[string]any{
+									"schema": map[string]any{
+										"id": "location",
+										"fields": map[string]any{
+											"id":          map[string]any{"type": "id", "isRequired": true},
+											"dateCreated": map[string]any{"type": "dateTime"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		source, err := codegen.Generate([]*mercury.EventContract{contract}, codegen.Options{})
+		require.NoError(t, err)
+
+		generated := string(source)
+		require.Contains(t, generated, "package events")
+		require.Contains(t, generated, "Location *GetLocationV1Location `json:\"location,omitempty\"`")
+		require.Contains(t, generated, "type GetLocationV1Location struct {\n\tDateCreated int64  `json:\"dateCreated,omitempty\"`\n\tId          string `json:\"id\"`\n}")
+		require.Contains(t, generated, "type GetLocationV1Target struct {\n}")
+
+		requireCompiles(t, source)
+	})
+
+	t.Run("loads contracts from yaml", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "contract.yml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+eventSignatures:
+  will-send-vip::v1:
+    responsePayloadSchema:
+      id: willSendVipResponse
+      fields:
+        messages:
+          type: text
+          isArray: true
+`), 0o644))
+
+		contract, err := codegen.LoadContract(path)
+		require.NoError(t, err)
+		require.Equal(t, []string{"will-send-vip::v1"}, contract.Fqens())
+
+		field := contract.EventSignatures["will-send-vip::v1"].ResponsePayloadSchema.Fields["messages"]
+		require.Equal(t, "text", field.Type)
+		require.True(t, field.IsArray)
+	})
+
+	t.Run("fetches contracts from Mercury", func(t *testing.T) {
+		testkit.BeforeEach(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.MakeEventReturnResponses("get-event-contracts::v2020_12_25", []mercury.ResponsePayload{
+			{"contracts": []any{
+				testkit.GenerateWillSendVipEventSignature("acme"),
+				testkit.GenerateWillSendVipEventSignature("other"),
+			}},
+		})
+
+		contracts, err := codegen.FetchContracts(context.Background(), client)
+		require.NoError(t, err)
+		require.Len(t, contracts, 2)
+		require.Equal(t, []string{"acme.will-send-vip::v1"}, contracts[0].Fqens())
+		require.Equal(t, []string{"other.will-send-vip::v1"}, contracts[1].Fqens())
+
+		source, err := codegen.Generate(contracts, codegen.Options{})
+		require.NoError(t, err)
+		requireCompiles(t, source)
+	})
+
+	t.Run("fails to fetch contracts when the response has none", func(t *testing.T) {
+		testkit.BeforeEach(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.MakeEventReturnResponses("get-event-contracts::v2020_12_25", []mercury.ResponsePayload{{}})
+
+		_, err = codegen.FetchContracts(context.Background(), client)
+		require.ErrorContains(t, err, "contracts field not found in response")
+	})
+
+	t.Run("fails to fetch contracts when the emit fails", func(t *testing.T) {
+		testkit.BeforeEach(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.MakeEventReturnError("get-event-contracts::v2020_12_25", &mercury.SpruceError{Code: "UNAUTHORIZED_ACCESS"})
+
+		_, err = codegen.FetchContracts(context.Background(), client)
+		require.Error(t, err)
+	})
+}
+
+// requireCompiles type-checks generated source against the export data of its
+// imports, which go list builds into the cache.
+func requireCompiles(t *testing.T, source []byte) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "generated.go", source, 0)
+	require.NoError(t, err)
+
+	args := []string{"list", "-export", "-deps", "-f", "{{.ImportPath}}={{.Export}}"}
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		require.NoError(t, err)
+		args = append(args, path)
+	}
+
+	output, err := exec.Command("go", args...).Output()
+	require.NoError(t, err)
+
+	exports := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		path, export, _ := strings.Cut(line, "=")
+		exports[path] = export
+	}
+
+	config := types.Config{
+		Importer: importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+			if exports[path] == "" {
+				return nil, fmt.Errorf("no export data for %s", path)
+			}
+			return os.Open(exports[path])
+		}),
+	}
+
+	_, err = config.Check("generated", fset, []*ast.File{file}, nil)
+	require.NoError(t, err, string(source))
+}
@@ -0,0 +1,62 @@
+package codegen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadContract reads an event contract from a .json, .yml or .yaml file.
+func LoadContract(path string) (*mercury.EventContract, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &values)
+	default:
+		err = json.Unmarshal(data, &values)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract '%s': %w", path, err)
+	}
+
+	return mercury.ParseEventContract(values)
+}
+
+// FetchContracts loads every contract the client can see through
+// get-event-contracts.
+func FetchContracts(ctx context.Context, client mercury.MercuryClient) ([]*mercury.EventContract, error) {
+	responses, err := client.EmitContext(ctx, "get-event-contracts::v2020_12_25")
+	if err != nil {
+		return nil, err
+	}
+
+	var contracts []*mercury.EventContract
+	for _, response := range responses {
+		values, ok := response["contracts"].([]any)
+		if !ok {
+			return nil, fmt.Errorf("contracts field not found in response")
+		}
+
+		for _, value := range values {
+			contract, err := mercury.ParseEventContract(value)
+			if err != nil {
+				return nil, err
+			}
+			contracts = append(contracts, contract)
+		}
+	}
+
+	return contracts, nil
+}
@@ -0,0 +1,73 @@
+package mercury
+
+type (
+	// AggregateResult is everything every responder sent back for one emit.
+	AggregateResult struct {
+		Fqen           string
+		TotalContracts int
+		TotalResponses int
+		TotalErrors    int
+		Responses      []ResponderResult
+	}
+
+	ResponderResult struct {
+		ResponderRef string
+		Payload      ResponsePayload
+		Errors       []*SpruceError
+	}
+)
+
+func newAggregateResult(event string, aggregateResponse MercuryAggregateResponse) *AggregateResult {
+	result := &AggregateResult{
+		Fqen:           event,
+		TotalContracts: int(aggregateResponse.TotalContracts),
+		TotalResponses: int(aggregateResponse.TotalResponses),
+		TotalErrors:    int(aggregateResponse.TotalErrors),
+		Responses:      make([]ResponderResult, len(aggregateResponse.Responses)),
+	}
+
+	for i, single := range aggregateResponse.Responses {
+		responder := ResponderResult{
+			ResponderRef: single.ResponderRef,
+			Payload:      single.Payload,
+		}
+
+		for _, rawErr := range single.Errors {
+			responder.Errors = append(responder.Errors, parseSpruceError(rawErr, event, single.ResponderRef))
+		}
+
+		result.Responses[i] = responder
+	}
+
+	return result
+}
+
+// Payloads returns the payloads of every responder that did not error.
+func (r *AggregateResult) Payloads() []ResponsePayload {
+	payloads := []ResponsePayload{}
+	for _, responder := range r.Responses {
+		if len(responder.Errors) == 0 {
+			payloads = append(payloads, responder.Payload)
+		}
+	}
+	return payloads
+}
+
+// Errors returns the errors of every responder that failed.
+func (r *AggregateResult) Errors() []*SpruceError {
+	var errs []*SpruceError
+	for _, responder := range r.Responses {
+		errs = append(errs, responder.Errors...)
+	}
+	return errs
+}
+
+// Err returns an *AggregateError holding every responder error, or nil if no
+// responder failed.
+func (r *AggregateResult) Err() error {
+	errs := r.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	return &AggregateError{Fqen: r.Fqen, Errors: errs}
+}
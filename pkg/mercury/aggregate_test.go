@@ -0,0 +1,86 @@
+package mercury_test
+
+import (
+	"context"
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitAggregate(t *testing.T) {
+
+	t.Run("returns successes alongside responder errors", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.MakeEventReturnResponses("will-send-vip::v1", []mercury.ResponsePayload{
+			{"messages": []any{"first"}},
+			{"errors": []any{map[string]any{"code": mercury.ErrorCodeListenerError, "friendlyMessage": "oops"}}},
+			{"messages": []any{"third"}},
+		})
+
+		result, err := client.EmitAggregate(context.Background(), "will-send-vip::v1")
+		require.NoError(t, err, "Responder errors should not be returned as the emit error")
+
+		require.Equal(t, 3, result.TotalContracts)
+		require.Equal(t, 3, result.TotalResponses)
+		require.Equal(t, 1, result.TotalErrors)
+		require.Len(t, result.Responses, 3)
+
+		require.Equal(t, "fake-responder-1", result.Responses[0].ResponderRef)
+		require.Empty(t, result.Responses[0].Errors)
+		require.Equal(t, "fake-responder-2", result.Responses[1].ResponderRef)
+		require.Len(t, result.Responses[1].Errors, 1)
+		require.Equal(t, "fake-responder-2", result.Responses[1].Errors[0].ResponderRef)
+
+		require.Equal(t, []mercury.ResponsePayload{
+			{"messages": []any{"first"}},
+			{"messages": []any{"third"}},
+		}, result.Payloads())
+
+		require.ErrorIs(t, result.Err(), mercury.ErrListenerError)
+	})
+
+	t.Run("emit still fails when any responder errors", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.MakeEventReturnResponses("will-send-vip::v1", []mercury.ResponsePayload{
+			{"messages": []any{"first"}},
+			{"errors": []any{map[string]any{"code": mercury.ErrorCodeListenerError}}},
+		})
+
+		responses, err := client.Emit("will-send-vip::v1")
+		require.Nil(t, responses)
+		require.ErrorIs(t, err, mercury.ErrListenerError)
+	})
+
+	t.Run("result has no error when every responder succeeds", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.MakeEventReturnResponses("will-send-vip::v1", []mercury.ResponsePayload{
+			{"messages": []any{"first"}},
+		})
+
+		result, err := client.EmitAggregate(context.Background(), "will-send-vip::v1")
+		require.NoError(t, err)
+		require.NoError(t, result.Err())
+		require.Empty(t, result.Errors())
+	})
+
+	t.Run("returns transport errors as the emit error", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		result, err := client.EmitAggregate(context.Background(), "not-registered::v1")
+		require.Nil(t, result)
+		require.Error(t, err)
+	})
+}
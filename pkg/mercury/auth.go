@@ -0,0 +1,95 @@
+package mercury
+
+import (
+	"context"
+	"fmt"
+
+	spruce "github.com/sprucelabsai-community/spruce-core-schemas/v41/pkg/schemas"
+	schemas "github.com/sprucelabsai-community/spruce-core-schemas/v41/pkg/schemas/spruce/v2020_07_22"
+)
+
+// AuthType is the kind of session whoami reports.
+type AuthType string
+
+const (
+	AuthTypeAnonymous     AuthType = "anonymous"
+	AuthTypeAuthenticated AuthType = "authenticated"
+)
+
+// Auth is who Mercury thinks the client is. Raw holds the auth map exactly as
+// Mercury sent it.
+type Auth struct {
+	Type   AuthType
+	Person *spruce.Person
+	Skill  *spruce.Skill
+	Raw    map[string]any
+}
+
+// WhoAmI asks Mercury who the client is authenticated as and refreshes
+// CurrentAuth with the answer.
+func (c *Client) WhoAmI(ctx context.Context) (*Auth, error) {
+	results, err := c.EmitContext(ctx, "whoami::v2020_12_25")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("whoami returned no responses")
+	}
+
+	authType, ok := results[0]["type"].(string)
+	if !ok {
+		return nil, fmt.Errorf("type field not found in response")
+	}
+
+	auth := &Auth{Type: AuthType(authType)}
+
+	if values, ok := results[0]["auth"].(map[string]any); ok {
+		if err := auth.parse(values); err != nil {
+			return nil, err
+		}
+	}
+
+	c.setAuth(*auth)
+
+	return auth, nil
+}
+
+// CurrentAuth returns the auth from the last Authenticate, ConfirmPin or WhoAmI
+// without asking Mercury. It is anonymous until one of them succeeds.
+func (c *Client) CurrentAuth() Auth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.currentAuth == nil {
+		return Auth{Type: AuthTypeAnonymous}
+	}
+	return *c.currentAuth
+}
+
+func (c *Client) setAuth(auth Auth) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.currentAuth = &auth
+}
+
+func (a *Auth) parse(values map[string]any) error {
+	a.Raw = values
+
+	if skillValues, ok := values["skill"].(map[string]any); ok {
+		skill, err := schemas.MakeSkill(skillValues)
+		if err != nil {
+			return fmt.Errorf("failed to parse skill: %w", err)
+		}
+		a.Skill = skill
+	}
+
+	if personValues, ok := values["person"].(map[string]any); ok {
+		person, err := schemas.MakePerson(personValues)
+		if err != nil {
+			return fmt.Errorf("failed to parse person: %w", err)
+		}
+		a.Person = person
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package mercury_test
+
+import (
+	"context"
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuth(t *testing.T) {
+
+	t.Run("is anonymous before authenticating", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		require.Equal(t, mercury.Auth{Type: mercury.AuthTypeAnonymous}, client.CurrentAuth())
+	})
+
+	t.Run("asks Mercury who the client is", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		raw := map[string]any{"skill": map[string]any{"id": "skill-1", "name": "Skill", "slug": "skill", "apiKey": "key-1"}}
+		fake.MakeEventReturnResponses("whoami::v2020_12_25", []mercury.ResponsePayload{
+			{"type": "authenticated", "auth": raw},
+		})
+
+		auth, err := client.WhoAmI(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, mercury.AuthTypeAuthenticated, auth.Type)
+		require.Equal(t, "skill-1", auth.Skill.Id)
+		require.Nil(t, auth.Person)
+		require.Equal(t, raw, auth.Raw)
+		require.Equal(t, *auth, client.CurrentAuth())
+	})
+
+	t.Run("reports anonymous clients", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.MakeEventReturnResponses("whoami::v2020_12_25", []mercury.ResponsePayload{
+			{"type": "anonymous", "auth": map[string]any{}},
+		})
+
+		auth, err := client.WhoAmI(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, mercury.AuthTypeAnonymous, auth.Type)
+		require.Nil(t, auth.Person)
+		require.Nil(t, auth.Skill)
+	})
+
+	t.Run("keeps current auth up to date after authenticating", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fakeAuthenticate(fake)
+		_, err = client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.NoError(t, err)
+
+		auth := client.CurrentAuth()
+		require.Equal(t, mercury.AuthTypeAuthenticated, auth.Type)
+		require.Equal(t, "person-1", auth.Person.Id)
+	})
+
+	t.Run("keeps current auth up to date after confirming a pin", func(t *testing.T) {
+		_, client := makeLoginClient(t, nil)
+
+		_, _, err := client.ConfirmPin(context.Background(), mercury.Challenge{Id: "challenge-1"}, "0000")
+		require.NoError(t, err)
+
+		auth := client.CurrentAuth()
+		require.Equal(t, mercury.AuthTypeAuthenticated, auth.Type)
+		require.Equal(t, "person-1", auth.Person.Id)
+	})
+
+	t.Run("goes back to anonymous when the token is rejected", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fakeAuthenticate(fake)
+		_, err = client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.NoError(t, err)
+
+		fake.MakeEventReturnResponses("authenticate::v2020_12_25", []mercury.ResponsePayload{
+			{"errors": []any{map[string]any{"code": "INVALID_AUTH_TOKEN"}}},
+		})
+		_, err = client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.Error(t, err)
+
+		require.Equal(t, mercury.AuthTypeAnonymous, client.CurrentAuth().Type)
+	})
+}
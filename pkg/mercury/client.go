@@ -1,21 +1,51 @@
 package mercury
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
-	schemas "github.com/sprucelabsai-community/spruce-core-schemas/v41/pkg/schemas/spruce/v2020_07_22"
 	ioClient "github.com/zishang520/socket.io/clients/socket/v3"
 	serverSocket "github.com/zishang520/socket.io/servers/socket/v3"
 	socketTypes "github.com/zishang520/socket.io/v3/pkg/types"
 )
 
 type (
+	// Client is safe for concurrent Emit, On, Off and Disconnect calls from
+	// many goroutines once Connect has returned.
 	Client struct {
-		socket Socket
+		socket      Socket
+		emitTimeout time.Duration
+
+		mu                sync.Mutex
+		lastAuth          *AuthenticatePayload
+		listenerEvents    []string
+		subscriptions     map[string][]*Subscription
+		registrations     map[string]*registration
+		onSessionRestored []func(error)
+		state             ConnectionState
+		stateListeners    []ConnectionStateListener
+		stateChanged      chan struct{}
+		restoring         bool
+		logger            *slog.Logger
+		logPayloads       bool
+		validator         *Validator
+		metrics           Metrics
+		emitRetry         *EmitRetryPolicy
+		queue             *offlineQueue
+		tokens            TokenStore
+		currentAuth       *Auth
+		listenerCtx       context.Context
+		cancelListenerCtx context.CancelFunc
+		middleware        []ListenerMiddleware
+		interceptors      []EmitInterceptor
 	}
 
 	Socket interface {
@@ -33,8 +63,8 @@ type (
 	}
 
 	emitResponse struct {
-		resp []ResponsePayload
-		err  error
+		result *AggregateResult
+		err    error
 	}
 )
 
@@ -44,119 +74,280 @@ func (c *Client) Connect(url string, opts MercuryClientOptions) error {
 		socketOptions.SetTimeout(time.Duration(opts.TimeoutSec * int(time.Second)))
 	}
 
-	socketOptions.SetReconnection(true)
-	socket, err := GetConnect()(url, socketOptions)
+	c.mu.Lock()
+	c.emitTimeout = time.Duration(opts.EmitTimeoutSec) * time.Second
+	if opts.EmitTimeoutSec <= 0 {
+		c.emitTimeout = defaultEmitTimeoutSec * time.Second
+	}
+	c.logger = opts.Logger
+	c.logPayloads = opts.LogPayloads
+	c.validator = opts.Validator
+	c.metrics = opts.Metrics
+	c.emitRetry = opts.EmitRetry
+	if opts.OfflineQueue != nil {
+		c.queue = newOfflineQueue(*opts.OfflineQueue)
+	}
+	c.tokens = opts.TokenStore
+	c.mu.Unlock()
 
-	if err != nil {
-		return err
+	policy := opts.ReconnectPolicy.withDefaults()
+	applyReconnectPolicy(socketOptions, policy, opts.ShouldRetryConnect)
+
+	c.setState(ConnectionStateConnecting, nil)
+	c.log().Info("Connecting to Mercury", "url", url)
+
+	var socket Socket
+	for attempt := 1; ; attempt++ {
+		var err error
+		socket, err = dial(url, socketOptions)
+		if err == nil {
+			break
+		}
+
+		if !opts.ShouldRetryConnect || attempt >= policy.MaxConnectAttempts {
+			c.log().Error("Failed to connect to Mercury", "url", url, "attempts", attempt, "error", err)
+			c.setState(ConnectionStateFailed, err)
+			return err
+		}
+
+		delay := policy.delay(attempt)
+		c.log().Warn("Failed to connect to Mercury, retrying", "url", url, "attempt", attempt, "delay", delay, "error", err)
+		time.Sleep(delay)
 	}
 
+	c.mu.Lock()
 	c.socket = socket
+	events := slices.Clone(c.listenerEvents)
+	for _, event := range events {
+		socket.On(event, c.dispatcher(event))
+	}
+	c.mu.Unlock()
 
-	done := make(chan error, 1)
-	var once sync.Once
+	c.resumeStoredSession()
 
-	finish := func(err error) {
-		once.Do(func() {
-			done <- err
-		})
+	if len(events) > 0 {
+		if err := c.registerListeners(context.Background(), events...); err != nil {
+			err = fmt.Errorf("failed to register listeners added before connecting: %w", err)
+			c.log().Error("Failed to connect to Mercury", "url", url, "error", err)
+			socket.Disconnect()
+			c.mu.Lock()
+			c.socket = nil
+			c.mu.Unlock()
+			c.setState(ConnectionStateFailed, err)
+			return err
+		}
 	}
 
+	c.log().Info("Connected to Mercury", "url", url)
+	c.setState(ConnectionStateConnected, nil)
+
 	socket.On("connect", func(...any) {
-		finish(nil)
+		c.setRestoring(true)
+		go c.restoreSession()
 	})
 
 	socket.On("disconnect", func(args ...any) {
-		fmt.Println(append([]any{"Disconnected:"}, args...)...)
+		err := socketError("disconnect", args)
+		c.log().Warn("Disconnected from Mercury", "reason", err)
+		if c.queue != nil {
+			c.queue.setOffline()
+		}
+		c.cancelListeners()
+		c.setRestoring(true)
+		c.setState(ConnectionStateDisconnected, err)
 	})
 
 	socket.On("error", func(args ...any) {
-		fmt.Println(append([]any{"Error:"}, args...)...)
+		err := socketError("error", args)
+		c.log().Error("Socket error", "error", err)
+		c.reportError(err)
+	})
+
+	socket.On("reconnect_attempt", func(args ...any) {
+		c.log().Info("Reconnecting to Mercury", "attempt", firstArg(args))
+		c.setState(ConnectionStateReconnecting, nil)
+	})
+
+	socket.On("reconnect", func(...any) {
+		c.log().Info("Reconnected to Mercury", "url", url)
+		c.meter().Reconnected()
+		c.setState(ConnectionStateReconnected, nil)
 	})
 
 	socket.On("reconnect_error", func(args ...any) {
-		fmt.Println(append([]any{"Reconnect Error:"}, args...)...)
+		err := socketError("reconnect_error", args)
+		c.log().Warn("Reconnect attempt failed", "error", err)
+		c.setState(ConnectionStateReconnecting, err)
 	})
 
-	socket.On("connect_error", func(args ...any) {
-		var connErr error
-		if len(args) > 0 {
-			if errVal, ok := args[0].(error); ok && errVal != nil {
-				connErr = errVal
-			} else {
-				connErr = fmt.Errorf("socket connect_error: %v", args[0])
-			}
-		} else {
-			connErr = fmt.Errorf("socket connect_error")
-		}
+	socket.On("reconnect_failed", func(args ...any) {
+		err := socketError("reconnect_failed", args)
+		c.log().Error("Gave up reconnecting to Mercury", "error", err)
+		c.setState(ConnectionStateFailed, err)
+	})
+
+	return nil
+}
 
-		finish(connErr)
+// dial opens a socket and waits for it to connect or fail.
+func dial(url string, socketOptions ioClient.OptionsInterface) (Socket, error) {
+	socket, err := GetConnect()(url, socketOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+	var once sync.Once
+
+	finish := func(err error) {
+		once.Do(func() {
+			done <- err
+		})
+	}
+
+	socket.On("connect", func(...any) {
+		finish(nil)
+	})
+
+	socket.On("connect_error", func(args ...any) {
+		finish(socketError("connect_error", args))
 	})
 
 	if socket.Connected() {
 		finish(nil)
 	}
 
-	waitErr := <-done
-	if waitErr != nil {
-		if c.socket != nil {
-			c.socket.Disconnect()
-			c.socket = nil
-		}
-		return waitErr
+	if err := <-done; err != nil {
+		socket.Disconnect()
+		return nil, err
 	}
 
-	return nil
+	return socket, nil
 }
 
 func (c *Client) Disconnect() {
-	if c.socket != nil {
-		c.socket.Disconnect()
+	if socket := c.currentSocket(); socket != nil {
+		c.log().Info("Disconnecting from Mercury")
+		socket.Disconnect()
+		c.cancelListeners()
+		c.setState(ConnectionStateDisconnected, nil)
 	}
 }
 
+// IsConnected reports whether the socket is connected. It is false before
+// Connect and after a failed connect.
 func (c *Client) IsConnected() bool {
-	return c.socket.Connected()
+	socket := c.currentSocket()
+	return socket != nil && socket.Connected()
+}
+
+func (c *Client) currentSocket() Socket {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.socket
 }
 
 func (c *Client) Emit(event string, args ...TargetAndPayload) ([]ResponsePayload, error) {
+	return c.EmitContext(context.Background(), event, args...)
+}
 
-	done := make(chan emitResponse, 1)
+// EmitContext emits like Emit but gives up when ctx is done. If ctx has no
+// deadline, the client's default emit timeout is applied. Acks that arrive
+// after the context is done are dropped.
+func (c *Client) EmitContext(ctx context.Context, event string, args ...TargetAndPayload) ([]ResponsePayload, error) {
+	result, err := c.EmitAggregate(ctx, event, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
 
+	return result.Payloads(), nil
+}
+
+// EmitAggregate emits and returns every responder's payload and errors. The
+// returned error is only set when the emit itself fails; responder errors are
+// left on the result.
+func (c *Client) EmitAggregate(ctx context.Context, event string, args ...TargetAndPayload) (*AggregateResult, error) {
 	targetAndPayload := TargetAndPayload{}
 
 	if len(args) > 0 {
 		targetAndPayload = args[0]
 	}
 
-	mappedEventName := ToSocketName(event)
+	return c.emitChain()(ctx, event, targetAndPayload)
+}
 
-	c.socket.Emit(mappedEventName, targetAndPayload, func(response []any, err error) {
-		if len(response) > 0 {
-			data, err := json.Marshal(response[0])
-			if err != nil {
-				done <- emitResponse{nil, err}
-				return
-			}
+func (c *Client) emitAggregate(ctx context.Context, event string, targetAndPayload TargetAndPayload) (*AggregateResult, error) {
+	if c.validator != nil {
+		if err := c.validator.ValidateEmit(event, targetAndPayload); err != nil {
+			return nil, err
+		}
+	}
 
-			var aggregateResponse MercuryAggregateResponse
-			if err := json.Unmarshal(data, &aggregateResponse); err != nil {
-				done <- emitResponse{nil, err}
-				return
+	timeout := c.emitTimeout
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline {
+		timeout = time.Until(deadline)
+	}
+
+	start := time.Now()
+	c.log().Debug("Emitting", "event", event, c.payloadAttr(targetAndPayload), "timeout", timeout)
+
+	result, err := c.emit(ctx, event, targetAndPayload)
+	duration := time.Since(start)
+	c.meter().EmitFinished(event, duration, result, err)
+
+	if err != nil {
+		c.log().Warn("Emit failed", "event", event, "duration", duration, "error", err)
+		return nil, err
+	}
+
+	c.log().Debug("Emit finished", "event", event, "duration", duration, "responses", result.TotalResponses, "errors", result.TotalErrors)
+
+	if c.validator != nil {
+		for _, payload := range result.Payloads() {
+			if err := c.validator.ValidateResponse(event, payload); err != nil {
+				return nil, err
 			}
+		}
+	}
 
-			singleResponses := aggregateResponse.Responses
-			var resp []ResponsePayload
-			for _, single := range singleResponses {
-				if len(single.Errors) > 0 {
-					errMsg := single.Errors[0]
-					if errMsg != "" {
-						err = fmt.Errorf("error from '%s' emit: %v", event, errMsg)
-						break
-					}
-				} else {
-					resp = append(resp, single.Payload)
+	return result, nil
+}
+
+func (c *Client) emit(ctx context.Context, event string, targetAndPayload TargetAndPayload) (*AggregateResult, error) {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline && c.emitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.emitTimeout)
+		defer cancel()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan emitResponse, 1)
+
+	mappedEventName := ToSocketName(event)
+
+	socket := c.currentSocket()
+	if socket == nil {
+		return nil, ErrNotConnected
+	}
+
+	send := func() error {
+		return socket.Emit(mappedEventName, targetAndPayload, func(response []any, err error) {
+			if len(response) > 0 {
+				var aggregateResponse MercuryAggregateResponse
+				if err := mapToStruct(response[0], &aggregateResponse); err != nil {
+					done <- emitResponse{nil, err}
+					return
 				}
+
+				done <- emitResponse{newAggregateResult(event, aggregateResponse), nil}
+				return
 			}
 
 			if err != nil {
@@ -164,21 +355,27 @@ func (c *Client) Emit(event string, args ...TargetAndPayload) ([]ResponsePayload
 				return
 			}
 
-			if resp == nil {
-				resp = []ResponsePayload{}
-			}
-
-			done <- emitResponse{resp, err}
-			return
-		}
-
-		done <- emitResponse{nil, err}
+			done <- emitResponse{&AggregateResult{Fqen: event}, nil}
+		})
+	}
 
-	})
+	var emitErr error
+	if c.queue != nil && !isSessionRestore(ctx) {
+		emitErr = c.queue.send(ctx, send)
+	} else {
+		emitErr = send()
+	}
 
-	emitResponse := <-done
+	if emitErr != nil {
+		return nil, emitErr
+	}
 
-	return emitResponse.resp, emitResponse.err
+	select {
+	case emitResponse := <-done:
+		return emitResponse.result, emitResponse.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 func ToSocketName(event string) string {
@@ -186,7 +383,11 @@ func ToSocketName(event string) string {
 }
 
 func (c *Client) Authenticate(opts AuthenticatePayload) (*AuthenticatResponse, error) {
-	results, err := c.Emit("authenticate::v2020_12_25", TargetAndPayload{
+	return c.authenticate(context.Background(), opts)
+}
+
+func (c *Client) authenticate(ctx context.Context, opts AuthenticatePayload) (*AuthenticatResponse, error) {
+	results, err := c.EmitContext(ctx, "authenticate::v2020_12_25", TargetAndPayload{
 		Payload: map[string]any{
 			"skillId": opts.SkillId,
 			"apiKey":  opts.ApiKey,
@@ -195,47 +396,235 @@ func (c *Client) Authenticate(opts AuthenticatePayload) (*AuthenticatResponse, e
 	})
 
 	if err != nil {
+		if opts.Token != "" && isAuthFailure(err) {
+			c.forgetToken(opts.Token)
+		}
 		return nil, err
 	}
 
-	auth, ok := results[0]["auth"].(map[string]any)
-	if !ok || len(auth) == 0 {
+	if len(results) == 0 {
+		return nil, fmt.Errorf("authenticate returned no responses")
+	}
+
+	values, ok := results[0]["auth"].(map[string]any)
+	if !ok || len(values) == 0 {
 		return nil, fmt.Errorf("auth field not found in response")
 	}
 
-	authResponse := &AuthenticatResponse{}
+	auth := Auth{Type: AuthTypeAuthenticated}
+	if err := auth.parse(values); err != nil {
+		return nil, err
+	}
 
-	if skillValues, ok := auth["skill"].(map[string]any); ok {
-		skill, err := schemas.MakeSkill(skillValues)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse skill: %w", err)
+	c.rememberAuth(opts)
+	c.setAuth(auth)
+	if opts.Token != "" {
+		c.storeToken(opts.Token)
+	}
+
+	return &AuthenticatResponse{Skill: auth.Skill, Person: auth.Person}, nil
+}
+
+// On adds listener to event. Many listeners can share one event; Mercury is
+// only told about the event when the first one is added. If Mercury refuses
+// the registration, the listener is removed again and the error returned.
+func (c *Client) On(event string, listener MercuryListener, opts ...ListenerOptions) (*Subscription, error) {
+	return c.OnContext(event, toContextListener(listener), opts...)
+}
+
+// OnContext listens like On, but passes the listener a context that is
+// cancelled when the client disconnects or the listener's Timeout passes.
+func (c *Client) OnContext(event string, listener ContextListener, opts ...ListenerOptions) (*Subscription, error) {
+	subscription := c.newSubscription(event, listener, opts...)
+	if err := c.subscribe(context.Background(), subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// OnMany adds every listener at once, telling Mercury about all the new events
+// in a single register-listeners emit. Either every listener is added or none
+// are.
+func (c *Client) OnMany(listeners map[string]MercuryListener, opts ...ListenerOptions) (map[string]*Subscription, error) {
+	contextListeners := make(map[string]ContextListener, len(listeners))
+	for event, listener := range listeners {
+		contextListeners[event] = toContextListener(listener)
+	}
+	return c.OnManyContext(contextListeners, opts...)
+}
+
+// OnManyContext adds many context listeners at once, the same way OnMany does.
+func (c *Client) OnManyContext(listeners map[string]ContextListener, opts ...ListenerOptions) (map[string]*Subscription, error) {
+	subscriptions := make([]*Subscription, 0, len(listeners))
+	for _, event := range slices.Sorted(maps.Keys(listeners)) {
+		subscriptions = append(subscriptions, c.newSubscription(event, listeners[event], opts...))
+	}
+
+	if err := c.subscribe(context.Background(), subscriptions...); err != nil {
+		return nil, err
+	}
+
+	added := make(map[string]*Subscription, len(subscriptions))
+	for _, subscription := range subscriptions {
+		added[subscription.event] = subscription
+	}
+	return added, nil
+}
+
+func toContextListener(listener MercuryListener) ContextListener {
+	if listener == nil {
+		return nil
+	}
+	return func(_ context.Context, targetAndPayload TargetAndPayload) (any, error) {
+		response := listener(targetAndPayload)
+		if err, ok := response.(error); ok && err != nil {
+			return nil, err
 		}
-		authResponse.Skill = skill
+		return response, nil
 	}
+}
 
-	if personValues, ok := auth["person"].(map[string]any); ok {
-		person, err := schemas.MakePerson(personValues)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse person: %w", err)
+func (c *Client) newSubscription(event string, listener ContextListener, opts ...ListenerOptions) *Subscription {
+	subscription := &Subscription{
+		client:   c,
+		event:    event,
+		listener: listener,
+	}
+
+	if len(opts) > 0 {
+		subscription.timeout = opts[0].Timeout
+		if opts[0].isPooled() {
+			subscription.pool = newListenerPool(opts[0])
 		}
-		authResponse.Person = person
 	}
 
-	return authResponse, nil
+	return subscription
 }
 
-func (c *Client) On(event string, listener MercuryListener) {
-	c.Emit("register-listeners::v2020_12_25", TargetAndPayload{
-		Payload: map[string]any{
-			"events": []map[string]string{
-				{
-					"eventName": event,
-				},
+// subscribe adds subscriptions and registers the events that had no listeners
+// yet. Listeners added before Connect are registered when it is called.
+// Subscriptions for an event whose registration is still in flight wait for it
+// and are rolled back with it if it fails.
+func (c *Client) subscribe(ctx context.Context, subscriptions ...*Subscription) error {
+	var events []string
+	var pending []*registration
+
+	c.mu.Lock()
+	if c.subscriptions == nil {
+		c.subscriptions = map[string][]*Subscription{}
+	}
+	for _, subscription := range subscriptions {
+		event := subscription.event
+		isFirst := len(c.subscriptions[event]) == 0
+		c.subscriptions[event] = append(c.subscriptions[event], subscription)
+		if isFirst {
+			events = append(events, event)
+			c.listenerEvents = append(c.listenerEvents, event)
+			if c.socket != nil {
+				c.socket.On(event, c.dispatcher(event))
+			}
+			continue
+		}
+
+		if reg := c.registrations[event]; reg != nil {
+			reg.subscriptions = append(reg.subscriptions, subscription)
+			if !slices.Contains(pending, reg) {
+				pending = append(pending, reg)
+			}
+		}
+	}
+
+	var registering *registration
+	if len(events) > 0 && c.socket != nil {
+		registering = &registration{
+			done:          make(chan struct{}),
+			subscriptions: slices.Clone(subscriptions),
+		}
+		if c.registrations == nil {
+			c.registrations = map[string]*registration{}
+		}
+		for _, event := range events {
+			c.registrations[event] = registering
+		}
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	if registering != nil {
+		err := c.registerListeners(ctx, events...)
+		if err != nil {
+			err = fmt.Errorf("failed to register listeners for %s: %w", strings.Join(events, ", "), err)
+			errs = append(errs, err)
+		}
+		c.finishRegistration(registering, events, err)
+	}
+
+	for _, reg := range pending {
+		<-reg.done
+		if reg.err != nil {
+			errs = append(errs, reg.err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	// Registrations that failed already rolled back their own subscriptions,
+	// so this only undoes the ones that got through.
+	var registered []string
+	for _, subscription := range subscriptions {
+		if c.detach(subscription.event, subscription) && registering != nil && registering.err == nil {
+			registered = append(registered, subscription.event)
+		}
+	}
+
+	if len(registered) > 0 {
+		if _, err := c.Emit("unregister-listeners::v2020_12_25", TargetAndPayload{
+			Payload: map[string]any{
+				"fullyQualifiedEventNames": registered,
 			},
-		},
-	})
+		}); err != nil {
+			c.log().Warn("Failed to unregister listeners after a failed subscribe", "events", registered, "error", err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// registration is a register-listeners emit in flight. Subscriptions added for
+// its events while it is pending wait on done.
+type registration struct {
+	done          chan struct{}
+	err           error
+	subscriptions []*Subscription
+}
+
+// finishRegistration records the outcome of reg and wakes its waiters. On
+// failure every subscription that joined it is detached under the same lock,
+// so a later On for the event starts a fresh registration.
+func (c *Client) finishRegistration(reg *registration, events []string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	handler := func(args ...any) {
+	for _, event := range events {
+		if c.registrations[event] == reg {
+			delete(c.registrations, event)
+		}
+	}
+
+	if err != nil {
+		for _, subscription := range reg.subscriptions {
+			c.detachLocked(subscription.event, subscription)
+		}
+	}
+
+	reg.err = err
+	close(reg.done)
+}
+
+func (c *Client) dispatcher(event string) func(args ...any) {
+	return func(args ...any) {
 
 		var ack serverSocket.Ack
 		argLen := len(args)
@@ -247,72 +636,214 @@ func (c *Client) On(event string, listener MercuryListener) {
 			}
 		}
 
-		var handlerErr error
 		var targetAndPayload TargetAndPayload
 
 		if argLen > 0 {
 			if err := mapToStruct(args[0], &targetAndPayload); err != nil {
-				handlerErr = fmt.Errorf("failed to parse target and payload: %w", err)
+				sendListenerAck(ack, event, []listenerResult{{err: fmt.Errorf("failed to parse target and payload: %w", err)}})
+				return
 			}
 		}
 
-		var response any
+		subscriptions := c.subscriptionsFor(event)
+		results := make([]listenerResult, len(subscriptions))
 
-		if listener != nil && handlerErr == nil {
-			defer func() {
-				if recoverErr := recover(); recoverErr != nil {
-					handlerErr = fmt.Errorf("listener panic: %v", recoverErr)
-				}
-			}()
-			response = listener(targetAndPayload)
+		// Pooled listeners are queued right away so they keep the order
+		// events arrived in, then everything is acked together.
+		var pending sync.WaitGroup
+		isPooled := false
 
-			if errVal, ok := response.(error); ok && errVal != nil {
-				handlerErr = errVal
-				response = nil
+		for i, subscription := range subscriptions {
+			if subscription.pool == nil || subscription.listener == nil {
+				continue
 			}
-		}
 
-		if ack == nil {
-			return
+			isPooled = true
+			pending.Add(1)
+
+			submitted := subscription.pool.submit(func() {
+				c.invokeListener(event, subscription, targetAndPayload, func(response any, err error) {
+					results[i].response, results[i].err = response, err
+					pending.Done()
+				})
+			})
+
+			if !submitted {
+				c.log().Warn("Listener busy", "event", event)
+				results[i].err = listenerBusyError(event)
+				pending.Done()
+			}
 		}
 
-		if handlerErr != nil {
-			errorAck := map[string]any{
-				"errors": []any{
-					map[string]any{
-						"code":            "LISTENER_ERROR",
-						"friendlyMessage": handlerErr.Error(),
-						"fqen":            event,
-						"originalError":   handlerErr.Error(),
-					},
-				},
+		finish := func() {
+			for i, subscription := range subscriptions {
+				if subscription.pool != nil || subscription.listener == nil {
+					continue
+				}
+
+				// Inline listeners have no worker to hold, so one that times
+				// out is answered for and left to finish in the background.
+				answered := make(chan struct{})
+				answer := func(response any, err error) {
+					results[i].response, results[i].err = response, err
+					close(answered)
+				}
+
+				if subscription.timeout > 0 {
+					go c.invokeListener(event, subscription, targetAndPayload, answer)
+				} else {
+					c.invokeListener(event, subscription, targetAndPayload, answer)
+				}
+				<-answered
 			}
-			ack([]any{errorAck}, nil)
-			return
+
+			pending.Wait()
+			sendListenerAck(ack, event, results)
 		}
 
-		if response != nil {
-			ack([]any{response}, nil)
+		if isPooled {
+			go finish()
 			return
 		}
 
-		ack(nil, nil)
+		finish()
 	}
+}
+
+// invokeListener runs a subscription's listener with a context that is
+// cancelled on disconnect and passes its result to answer. With a timeout,
+// answer gets a LISTENER_TIMEOUT error as soon as the deadline passes, but
+// invokeListener only returns once the listener does, so a listener that
+// ignores its context keeps holding its pool worker.
+func (c *Client) invokeListener(event string, subscription *Subscription, targetAndPayload TargetAndPayload, answer func(any, error)) {
+	ctx := c.listenerContext()
+
+	if subscription.timeout <= 0 {
+		answer(c.runListener(ctx, event, subscription.listener, targetAndPayload))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, subscription.timeout)
+	defer cancel()
+
+	done := make(chan listenerResult, 1)
+	go func() {
+		response, err := c.runListener(ctx, event, subscription.listener, targetAndPayload)
+		done <- listenerResult{response, err}
+	}()
+
+	select {
+	case result := <-done:
+		answer(result.response, result.err)
+		return
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			c.log().Warn("Listener timed out", "event", event, "timeout", subscription.timeout)
+			answer(nil, listenerTimeoutError(event, subscription.timeout))
+		} else {
+			answer(nil, ctx.Err())
+		}
+	}
+
+	<-done
+}
+
+func (c *Client) runListener(ctx context.Context, event string, listener ContextListener, targetAndPayload TargetAndPayload) (response any, err error) {
+	start := time.Now()
+	c.log().Debug("Listener invoked", "event", event, c.payloadAttr(targetAndPayload))
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("listener panic: %v", recovered)
+			response = nil
+			c.log().Error("Listener panicked", "event", event, "panic", recovered)
+			c.meter().ListenerPanicked(event)
+		}
+		duration := time.Since(start)
+		c.meter().ListenerFinished(event, duration, err)
+		c.log().Debug("Listener finished", "event", event, "duration", duration, "error", err)
+	}()
 
-	c.socket.On(event, handler)
+	return c.listenerChain(listener)(ctx, event, targetAndPayload)
 }
 
-func (c *Client) Off(event string, listeners ...MercuryListener) {
+// Off removes the given subscriptions from event, or every listener when none
+// are passed. Mercury is only told once the last listener is gone.
+func (c *Client) Off(event string, subscriptions ...*Subscription) error {
+	if !c.detach(event, subscriptions...) {
+		return nil
+	}
+
 	_, err := c.Emit("unregister-listeners::v2020_12_25", TargetAndPayload{
 		Payload: map[string]any{
 			"fullyQualifiedEventNames": []string{event},
 		},
 	})
-
 	if err != nil {
-		// fmt.Println("Unregister listeners response error:", results, err)
+		return fmt.Errorf("failed to unregister listeners for %s: %w", event, err)
+	}
+
+	return nil
+}
+
+// detach removes subscriptions from event, or every subscription when none
+// are given. It reports whether the last listener went away while connected,
+// meaning Mercury should stop sending the event.
+func (c *Client) detach(event string, subscriptions ...*Subscription) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.detachLocked(event, subscriptions...)
+}
+
+// detachLocked is detach for callers that already hold c.mu.
+func (c *Client) detachLocked(event string, subscriptions ...*Subscription) bool {
+	remaining := slices.DeleteFunc(c.subscriptions[event], func(existing *Subscription) bool {
+		isRemoved := len(subscriptions) == 0 || slices.Contains(subscriptions, existing)
+		if isRemoved && existing.pool != nil {
+			existing.pool.stop()
+		}
+		return isRemoved
+	})
+	isLast := len(remaining) == 0
+	wasListening := slices.Contains(c.listenerEvents, event)
+	if isLast {
+		delete(c.subscriptions, event)
+		c.listenerEvents = slices.DeleteFunc(c.listenerEvents, func(existing string) bool {
+			return existing == event
+		})
+	} else {
+		c.subscriptions[event] = remaining
+	}
+
+	if !isLast || !wasListening || c.socket == nil {
+		return false
 	}
+
 	c.socket.Off(event, nil)
+	return true
+}
+
+func (c *Client) subscriptionsFor(event string) []*Subscription {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.subscriptions[event])
+}
+
+func (c *Client) registerListeners(ctx context.Context, events ...string) error {
+	eventNames := make([]map[string]string, len(events))
+	for i, event := range events {
+		eventNames[i] = map[string]string{
+			"eventName": event,
+		}
+	}
+
+	_, err := c.EmitContext(ctx, "register-listeners::v2020_12_25", TargetAndPayload{
+		Payload: map[string]any{
+			"events": eventNames,
+		},
+	})
+
+	return err
 }
 
 func mapToStruct(data any, out any) error {
@@ -323,6 +854,14 @@ func mapToStruct(data any, out any) error {
 	return json.Unmarshal(bytes, out)
 }
 
+// managerEvents are emitted by the socket.io manager instead of the socket.
+var managerEvents = map[string]bool{
+	"reconnect":         true,
+	"reconnect_attempt": true,
+	"reconnect_error":   true,
+	"reconnect_failed":  true,
+}
+
 var (
 	connectMu sync.RWMutex
 	connectFn ConnectFunc = defaultConnect
@@ -365,6 +904,9 @@ func (s *SocketIoClient) Emit(event string, args ...any) error {
 }
 
 func (s *SocketIoClient) On(event string, listeners ...socketTypes.EventListener) error {
+	if managerEvents[event] {
+		return s.socket.Io().On(socketTypes.EventName(event), listeners...)
+	}
 	return s.socket.On(socketTypes.EventName(event), listeners...)
 }
 
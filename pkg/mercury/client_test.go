@@ -0,0 +1,99 @@
+package mercury_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitContext(t *testing.T) {
+
+	t.Run("returns deadline exceeded when ack never arrives", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.On("never-acks::v1", func(args ...any) {})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		responses, err := client.EmitContext(ctx, "never-acks::v1")
+		require.Nil(t, responses, "Responses should be nil when deadline passes")
+		require.ErrorIs(t, err, context.DeadlineExceeded, "Should return deadline exceeded")
+	})
+
+	t.Run("returns canceled when context is cancelled", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.On("never-acks::v1", func(args ...any) {})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err = client.EmitContext(ctx, "never-acks::v1")
+		require.ErrorIs(t, err, context.Canceled, "Should return canceled")
+	})
+
+	t.Run("applies default emit timeout when context has no deadline", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{EmitTimeoutSec: 1})
+		require.NoError(t, err)
+
+		fake.On("never-acks::v1", func(args ...any) {})
+
+		_, err = client.EmitContext(context.Background(), "never-acks::v1")
+		require.ErrorIs(t, err, context.DeadlineExceeded, "Default emit timeout should apply")
+	})
+
+	t.Run("drops acks that arrive after the deadline", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		acked := make(chan struct{})
+		fake.On("late-ack::v1", func(args ...any) {
+			cb := testkit.PluckCallback(args)
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				cb([]any{mercury.ResponsePayload{"late": true}}, nil)
+				close(acked)
+			}()
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		_, err = client.EmitContext(ctx, "late-ack::v1")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		select {
+		case <-acked:
+		case <-time.After(time.Second):
+			t.Fatal("Late ack should not block")
+		}
+	})
+
+	t.Run("passes responses back before the deadline", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("fast-event::v1", func(targetAndPayload mercury.TargetAndPayload) any {
+			return mercury.ResponsePayload{"hello": "world"}
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		responses, err := client.EmitContext(ctx, "fast-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"hello": "world"}}, responses)
+	})
+}
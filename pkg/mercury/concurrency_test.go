@@ -0,0 +1,180 @@
+package mercury_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+	ioClient "github.com/zishang520/socket.io/clients/socket/v3"
+)
+
+// These tests are most useful with go test -race.
+func TestConcurrency(t *testing.T) {
+
+	t.Run("is not connected before Connect", func(t *testing.T) {
+		client := &mercury.Client{}
+		require.False(t, client.IsConnected())
+
+		_, err := client.Emit("any-event::v1")
+		require.ErrorIs(t, err, mercury.ErrNotConnected)
+
+		client.Disconnect()
+	})
+
+	t.Run("is not connected after a failed connect", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		mercury.SetConnect(func(string, ioClient.OptionsInterface) (mercury.Socket, error) {
+			return nil, errors.New("no route to host")
+		})
+
+		client := &mercury.Client{}
+		require.Error(t, client.Connect("https://mercury.test", mercury.MercuryClientOptions{}))
+		require.False(t, client.IsConnected())
+	})
+
+	t.Run("registers listeners added before Connect", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		mercury.SetConnect(testkit.FakeSocketConnect)
+
+		client := &mercury.Client{}
+		client.On("early-event::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"early": true}
+		})
+
+		require.NoError(t, client.Connect("https://mercury.test", mercury.MercuryClientOptions{}))
+
+		responses, err := client.Emit("early-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"early": true}}, responses)
+	})
+
+	t.Run("fails to connect when listeners added before Connect cannot be registered", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		mercury.SetConnect(testkit.FakeSocketConnect)
+		_, err := testkit.FakeSocketConnect("", nil)
+		require.NoError(t, err)
+		testkit.LastFakeSocket().MakeEventReturnError("register-listeners::v2020_12_25", errors.New("not allowed"))
+
+		client := &mercury.Client{}
+		client.On("early-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		err = client.Connect("https://mercury.test", mercury.MercuryClientOptions{})
+		require.ErrorContains(t, err, "failed to register listeners added before connecting")
+		require.Equal(t, mercury.ConnectionStateFailed, client.State())
+		require.False(t, client.IsConnected())
+
+		_, err = client.Emit("early-event::v1")
+		require.ErrorIs(t, err, mercury.ErrNotConnected)
+	})
+
+	t.Run("handles concurrent emits, ons and offs", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"ok": true}
+		})
+
+		// require must not be called off the test goroutine, so errors are
+		// collected and checked once every goroutine is done.
+		errs := make(chan error, 60)
+
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(3)
+
+			go func() {
+				defer wg.Done()
+				_, err := client.Emit("shared-event::v1")
+				errs <- err
+			}()
+
+			go func() {
+				defer wg.Done()
+				subscription, err := client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return nil })
+				if err != nil {
+					errs <- err
+					return
+				}
+				errs <- subscription.Off()
+			}()
+
+			go func() {
+				defer wg.Done()
+				event := fmt.Sprintf("event-%d::v1", i)
+				client.On(event, func(mercury.TargetAndPayload) any { return nil })
+				_, _ = client.Emit(event)
+				client.Off(event)
+				_ = client.IsConnected()
+				_ = client.State()
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		responses, err := client.Emit("shared-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"ok": true}}, responses)
+	})
+
+	t.Run("handles disconnecting while emitting", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+				_, _ = client.Emit("shared-event::v1")
+			}()
+
+			go func() {
+				defer wg.Done()
+				client.Disconnect()
+				fake.SetConnected(true)
+			}()
+		}
+
+		wg.Wait()
+	})
+
+	t.Run("handles reconnects while emitting", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+				_, _ = client.Emit("shared-event::v1")
+			}()
+
+			go func() {
+				defer wg.Done()
+				fake.SimulateReconnect()
+			}()
+		}
+
+		wg.Wait()
+	})
+}
@@ -0,0 +1,152 @@
+package mercury
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type (
+	ConnectionState int
+
+	ConnectionStateListener = func(state ConnectionState, err error)
+)
+
+var (
+	// ErrNotConnected is returned when emitting before Connect has succeeded.
+	ErrNotConnected = errors.New("not connected to Mercury")
+	// ErrConnectionFailed is returned while waiting for a connection that has
+	// given up reconnecting.
+	ErrConnectionFailed = errors.New("connection to Mercury failed")
+)
+
+const (
+	ConnectionStateDisconnected ConnectionState = iota
+	ConnectionStateConnecting
+	ConnectionStateConnected
+	ConnectionStateReconnecting
+	ConnectionStateReconnected
+	ConnectionStateFailed
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionStateDisconnected:
+		return "disconnected"
+	case ConnectionStateConnecting:
+		return "connecting"
+	case ConnectionStateConnected:
+		return "connected"
+	case ConnectionStateReconnecting:
+		return "reconnecting"
+	case ConnectionStateReconnected:
+		return "reconnected"
+	case ConnectionStateFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("ConnectionState(%d)", int(s))
+	}
+}
+
+// State returns the last known state of the connection to Mercury.
+func (c *Client) State() ConnectionState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// OnConnectionStateChange registers a callback that runs on every state change.
+// It also runs, without a state change, when the socket reports an error.
+func (c *Client) OnConnectionStateChange(listener ConnectionStateListener) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stateListeners = append(c.stateListeners, listener)
+}
+
+func (c *Client) setState(state ConnectionState, err error) {
+	c.mu.Lock()
+	c.state = state
+	listeners := slices.Clone(c.stateListeners)
+	c.notifyStateChanged()
+	c.mu.Unlock()
+
+	c.meter().ConnectionStateChanged(state)
+
+	for _, listener := range listeners {
+		listener(state, err)
+	}
+}
+
+// reportError tells state listeners about err without touching the state, so
+// an error racing a state change cannot put back a stale state.
+func (c *Client) reportError(err error) {
+	c.mu.Lock()
+	state := c.state
+	listeners := slices.Clone(c.stateListeners)
+	c.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(state, err)
+	}
+}
+
+func socketError(event string, args []any) error {
+	if len(args) == 0 {
+		return fmt.Errorf("socket %s", event)
+	}
+
+	if errVal, ok := args[0].(error); ok && errVal != nil {
+		return errVal
+	}
+
+	return fmt.Errorf("socket %s: %v", event, args[0])
+}
+
+// setRestoring marks whether the session still has to be restored after the
+// socket dropped. It is set on disconnect and cleared once restoreSession has
+// re-authenticated and re-registered listeners.
+func (c *Client) setRestoring(restoring bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.restoring = restoring
+	c.notifyStateChanged()
+}
+
+// notifyStateChanged wakes waitForConnection. Callers hold c.mu.
+func (c *Client) notifyStateChanged() {
+	if c.stateChanged != nil {
+		close(c.stateChanged)
+		c.stateChanged = nil
+	}
+}
+
+// waitForConnection blocks until the client is connected with its session
+// restored, the connection has failed for good, or ctx is done.
+func (c *Client) waitForConnection(ctx context.Context) error {
+	for {
+		c.mu.Lock()
+		state := c.state
+		restoring := c.restoring
+		if c.stateChanged == nil {
+			c.stateChanged = make(chan struct{})
+		}
+		changed := c.stateChanged
+		c.mu.Unlock()
+
+		switch state {
+		case ConnectionStateConnected, ConnectionStateReconnected:
+			if !restoring {
+				return nil
+			}
+		case ConnectionStateFailed:
+			return ErrConnectionFailed
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
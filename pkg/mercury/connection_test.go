@@ -0,0 +1,130 @@
+package mercury_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+	ioClient "github.com/zishang520/socket.io/clients/socket/v3"
+)
+
+func TestConnectionState(t *testing.T) {
+
+	t.Run("is disconnected before connect", func(t *testing.T) {
+		client := &mercury.Client{}
+		require.Equal(t, mercury.ConnectionStateDisconnected, client.State())
+	})
+
+	t.Run("moves through connecting to connected", func(t *testing.T) {
+		testkit.BeforeEach(t)
+		client := &mercury.Client{}
+		states := recordStates(client)
+
+		require.NoError(t, client.Connect("https://mercury.spruce.ai", mercury.MercuryClientOptions{}))
+		require.Equal(t, []mercury.ConnectionState{
+			mercury.ConnectionStateConnecting,
+			mercury.ConnectionStateConnected,
+		}, *states)
+		require.Equal(t, mercury.ConnectionStateConnected, client.State())
+	})
+
+	t.Run("reports failed when connect fails", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		connectErr := errors.New("no route to host")
+		mercury.SetConnect(func(string, ioClient.OptionsInterface) (mercury.Socket, error) {
+			return nil, connectErr
+		})
+
+		client := &mercury.Client{}
+		var lastErr error
+		client.OnConnectionStateChange(func(state mercury.ConnectionState, err error) {
+			lastErr = err
+		})
+
+		require.ErrorIs(t, client.Connect("https://mercury.spruce.ai", mercury.MercuryClientOptions{}), connectErr)
+		require.Equal(t, mercury.ConnectionStateFailed, client.State())
+		require.ErrorIs(t, lastErr, connectErr)
+	})
+
+	t.Run("reports reconnecting and reconnected after a blip", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var states []mercury.ConnectionState
+		var errs []error
+		client.OnConnectionStateChange(func(state mercury.ConnectionState, err error) {
+			states = append(states, state)
+			errs = append(errs, err)
+		})
+
+		fake.SimulateReconnect()
+
+		require.Equal(t, []mercury.ConnectionState{
+			mercury.ConnectionStateDisconnected,
+			mercury.ConnectionStateReconnecting,
+			mercury.ConnectionStateReconnected,
+		}, states)
+		require.ErrorContains(t, errs[0], "transport close")
+		require.Equal(t, mercury.ConnectionStateReconnected, client.State())
+	})
+
+	t.Run("reports socket errors without changing state", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var reported error
+		client.OnConnectionStateChange(func(state mercury.ConnectionState, err error) {
+			reported = err
+		})
+
+		fake.Trigger("error", errors.New("parse error"))
+		require.ErrorContains(t, reported, "parse error")
+		require.Equal(t, mercury.ConnectionStateConnected, client.State())
+	})
+
+	t.Run("keeps the latest state when errors race a disconnect", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fake.Trigger("error", errors.New("parse error"))
+			}()
+		}
+		fake.SimulateDisconnect()
+		wg.Wait()
+
+		require.Equal(t, mercury.ConnectionStateDisconnected, client.State())
+	})
+
+	t.Run("is disconnected after disconnect", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.Disconnect()
+		require.Equal(t, mercury.ConnectionStateDisconnected, client.State())
+	})
+
+	t.Run("state has readable names", func(t *testing.T) {
+		require.Equal(t, "reconnecting", mercury.ConnectionStateReconnecting.String())
+		require.Equal(t, "failed", mercury.ConnectionStateFailed.String())
+	})
+}
+
+func recordStates(client *mercury.Client) *[]mercury.ConnectionState {
+	states := &[]mercury.ConnectionState{}
+	client.OnConnectionStateChange(func(state mercury.ConnectionState, err error) {
+		*states = append(*states, state)
+	})
+	return states
+}
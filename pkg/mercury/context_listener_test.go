@@ -0,0 +1,112 @@
+package mercury_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextListeners(t *testing.T) {
+
+	t.Run("passes back responses", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.OnContext("context-event::v1", func(ctx context.Context, targetAndPayload mercury.TargetAndPayload) (any, error) {
+			require.NotNil(t, ctx)
+			return map[string]any{"name": targetAndPayload.Payload["name"]}, nil
+		})
+
+		responses, err := client.Emit("context-event::v1", mercury.TargetAndPayload{
+			Payload: map[string]any{"name": "Tay"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"name": "Tay"}}, responses)
+	})
+
+	t.Run("passes back returned errors", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.OnContext("context-event::v1", func(context.Context, mercury.TargetAndPayload) (any, error) {
+			return nil, errors.New("could not do it")
+		})
+
+		_, err = client.Emit("context-event::v1")
+		require.ErrorIs(t, err, mercury.ErrListenerError)
+	})
+
+	t.Run("answers with a timeout error when the listener runs too long", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		cancelled := make(chan struct{}, 1)
+		client.OnContext("slow-event::v1", func(ctx context.Context, _ mercury.TargetAndPayload) (any, error) {
+			<-ctx.Done()
+			cancelled <- struct{}{}
+			return nil, ctx.Err()
+		}, mercury.ListenerOptions{Timeout: 20 * time.Millisecond})
+
+		_, err = client.Emit("slow-event::v1")
+		require.ErrorIs(t, err, mercury.ErrListenerTimeout)
+		require.ErrorContains(t, err, "'slow-event::v1' did not finish within 20ms")
+		receiveSignal(t, cancelled)
+	})
+
+	t.Run("answers normally when the listener beats its timeout", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.OnContext("quick-event::v1", func(ctx context.Context, _ mercury.TargetAndPayload) (any, error) {
+			_, hasDeadline := ctx.Deadline()
+			return map[string]any{"hasDeadline": hasDeadline}, nil
+		}, mercury.ListenerOptions{Timeout: time.Second})
+
+		responses, err := client.Emit("quick-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"hasDeadline": true}}, responses)
+	})
+
+	t.Run("cancels the context when the client disconnects", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		started := make(chan struct{}, 1)
+		cancelled := make(chan struct{}, 1)
+		client.OnContext("long-event::v1", func(ctx context.Context, _ mercury.TargetAndPayload) (any, error) {
+			started <- struct{}{}
+			<-ctx.Done()
+			cancelled <- struct{}{}
+			return nil, ctx.Err()
+		}, mercury.ListenerOptions{MaxConcurrent: 1, QueueSize: 1})
+
+		fake.Trigger("long-event::v1")
+		receiveSignal(t, started)
+
+		fake.SimulateDisconnect()
+		receiveSignal(t, cancelled)
+	})
+
+	t.Run("wraps plain listeners", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("plain-event::v1", func(mercury.TargetAndPayload) any {
+			return errors.New("plain failure")
+		}, mercury.ListenerOptions{Timeout: time.Second})
+
+		_, err = client.Emit("plain-event::v1")
+		require.ErrorIs(t, err, mercury.ErrListenerError)
+	})
+}
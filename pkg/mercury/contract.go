@@ -0,0 +1,73 @@
+package mercury
+
+import "sort"
+
+type (
+	// EventContract is the shape skills send to register-events and Mercury
+	// returns from get-event-contracts.
+	EventContract struct {
+		EventSignatures map[string]EventSignature `json:"eventSignatures"`
+	}
+
+	EventSignature struct {
+		EmitPayloadSchema     *Schema `json:"emitPayloadSchema,omitempty"`
+		ResponsePayloadSchema *Schema `json:"responsePayloadSchema,omitempty"`
+	}
+
+	Schema struct {
+		Id     string                     `json:"id"`
+		Fields map[string]FieldDefinition `json:"fields,omitempty"`
+	}
+
+	FieldDefinition struct {
+		Type       string        `json:"type"`
+		IsRequired bool          `json:"isRequired,omitempty"`
+		IsArray    bool          `json:"isArray,omitempty"`
+		Options    *FieldOptions `json:"options,omitempty"`
+	}
+
+	FieldOptions struct {
+		Schema  *Schema        `json:"schema,omitempty"`
+		Choices []SelectChoice `json:"choices,omitempty"`
+	}
+
+	SelectChoice struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	}
+)
+
+// ParseEventContract converts a contract built from maps, like the ones
+// register-events accepts, into an *EventContract.
+func ParseEventContract(values any) (*EventContract, error) {
+	contract := &EventContract{}
+	if err := decode(values, contract, "contract"); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+// Fqens returns every event name in the contract, sorted.
+func (c *EventContract) Fqens() []string {
+	fqens := make([]string, 0, len(c.EventSignatures))
+	for fqen := range c.EventSignatures {
+		fqens = append(fqens, fqen)
+	}
+	sort.Strings(fqens)
+	return fqens
+}
+
+// SubSchema returns the schema nested in the named schema field, such as the
+// "target" or "payload" of an emitPayloadSchema.
+func (s *Schema) SubSchema(field string) *Schema {
+	if s == nil {
+		return nil
+	}
+
+	definition, ok := s.Fields[field]
+	if !ok || definition.Options == nil {
+		return nil
+	}
+
+	return definition.Options.Schema
+}
@@ -0,0 +1,122 @@
+package mercury
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	ErrorCodeInvalidEventName   = "INVALID_EVENT_NAME"
+	ErrorCodeUnauthorizedAccess = "UNAUTHORIZED_ACCESS"
+	ErrorCodeListenerError      = "LISTENER_ERROR"
+	ErrorCodeListenerBusy       = "LISTENER_BUSY"
+	ErrorCodeListenerTimeout    = "LISTENER_TIMEOUT"
+	ErrorCodeInvalidPhone       = "INVALID_PHONE"
+	ErrorCodeInvalidPin         = "INVALID_PIN"
+	ErrorCodeChallengeExpired   = "CHALLENGE_EXPIRED"
+	ErrorCodeUnknown            = "UNKNOWN_ERROR"
+)
+
+var (
+	ErrInvalidEventName   = &SpruceError{Code: ErrorCodeInvalidEventName}
+	ErrUnauthorizedAccess = &SpruceError{Code: ErrorCodeUnauthorizedAccess}
+	ErrListenerError      = &SpruceError{Code: ErrorCodeListenerError}
+	ErrListenerBusy       = &SpruceError{Code: ErrorCodeListenerBusy}
+	ErrListenerTimeout    = &SpruceError{Code: ErrorCodeListenerTimeout}
+	ErrInvalidPhone       = &SpruceError{Code: ErrorCodeInvalidPhone}
+	ErrInvalidPin         = &SpruceError{Code: ErrorCodeInvalidPin}
+	ErrChallengeExpired   = &SpruceError{Code: ErrorCodeChallengeExpired}
+)
+
+type (
+	// SpruceError is a single error reported by a responder to an emit.
+	SpruceError struct {
+		Code            string
+		FriendlyMessage string
+		Fqen            string
+		ResponderRef    string
+		Options         map[string]any
+	}
+
+	// AggregateError holds every error returned by every responder to an emit.
+	AggregateError struct {
+		Fqen   string
+		Errors []*SpruceError
+	}
+)
+
+func (e *SpruceError) Error() string {
+	if e.FriendlyMessage == "" {
+		return e.Code
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.FriendlyMessage)
+}
+
+// Is matches any *SpruceError with the same code, so errors.Is(err,
+// ErrInvalidEventName) works regardless of message or responder.
+func (e *SpruceError) Is(target error) bool {
+	t, ok := target.(*SpruceError)
+	if !ok {
+		return false
+	}
+	return t.Code != "" && t.Code == e.Code
+}
+
+func (e *AggregateError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("error from '%s' emit: %s", e.Fqen, strings.Join(messages, "; "))
+}
+
+func (e *AggregateError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// HasCode reports whether any error in err's tree is a *SpruceError with code.
+func HasCode(err error, code string) bool {
+	return errors.Is(err, &SpruceError{Code: code})
+}
+
+func parseSpruceError(raw any, fqen string, responderRef string) *SpruceError {
+	spruceErr := &SpruceError{
+		Fqen:         fqen,
+		ResponderRef: responderRef,
+	}
+
+	values, ok := raw.(map[string]any)
+	if !ok {
+		spruceErr.Code = ErrorCodeUnknown
+		spruceErr.FriendlyMessage = fmt.Sprint(raw)
+		return spruceErr
+	}
+
+	options, ok := values["options"].(map[string]any)
+	if !ok {
+		options = values
+	}
+
+	spruceErr.Options = options
+	spruceErr.Code, _ = options["code"].(string)
+	spruceErr.FriendlyMessage, _ = options["friendlyMessage"].(string)
+
+	if optionsFqen, ok := options["fqen"].(string); ok && optionsFqen != "" {
+		spruceErr.Fqen = optionsFqen
+	}
+
+	if spruceErr.Code == "" {
+		spruceErr.Code = ErrorCodeUnknown
+	}
+
+	if spruceErr.FriendlyMessage == "" {
+		spruceErr.FriendlyMessage, _ = values["message"].(string)
+	}
+
+	return spruceErr
+}
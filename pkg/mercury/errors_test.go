@@ -0,0 +1,82 @@
+package mercury_test
+
+import (
+	"errors"
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+
+	t.Run("spruce errors match on code", func(t *testing.T) {
+		err := &mercury.SpruceError{Code: mercury.ErrorCodeUnauthorizedAccess, FriendlyMessage: "nope"}
+		require.ErrorIs(t, err, mercury.ErrUnauthorizedAccess)
+		require.NotErrorIs(t, err, mercury.ErrInvalidEventName)
+		require.Equal(t, "UNAUTHORIZED_ACCESS: nope", err.Error())
+	})
+
+	t.Run("listener errors come back as aggregate error", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("fails::v1", func(targetAndPayload mercury.TargetAndPayload) any {
+			return errors.New("boom")
+		})
+
+		responses, err := client.Emit("fails::v1")
+		require.Nil(t, responses)
+		require.ErrorIs(t, err, mercury.ErrListenerError)
+		require.True(t, mercury.HasCode(err, mercury.ErrorCodeListenerError))
+
+		var aggregateErr *mercury.AggregateError
+		require.ErrorAs(t, err, &aggregateErr)
+		require.Equal(t, "fails::v1", aggregateErr.Fqen)
+		require.Len(t, aggregateErr.Errors, 1)
+
+		var spruceErr *mercury.SpruceError
+		require.ErrorAs(t, err, &spruceErr)
+		require.Equal(t, "boom", spruceErr.FriendlyMessage)
+		require.Equal(t, "fails::v1", spruceErr.Fqen)
+		require.Equal(t, "fake-responder-1", spruceErr.ResponderRef)
+		require.Contains(t, err.Error(), "error from 'fails::v1' emit")
+	})
+
+	t.Run("keeps every error from a responder", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.On("many-errors::v1", func(args ...any) {
+			cb := testkit.PluckCallback(args)
+			cb([]any{mercury.ResponsePayload{
+				"errors": []any{
+					map[string]any{
+						"options": map[string]any{
+							"code":            mercury.ErrorCodeInvalidEventName,
+							"friendlyMessage": "bad name",
+							"eventName":       "many-errors::v1",
+						},
+					},
+					map[string]any{
+						"code":            mercury.ErrorCodeUnauthorizedAccess,
+						"friendlyMessage": "not allowed",
+					},
+				},
+			}}, nil)
+		})
+
+		_, err = client.Emit("many-errors::v1")
+		require.ErrorIs(t, err, mercury.ErrInvalidEventName)
+		require.ErrorIs(t, err, mercury.ErrUnauthorizedAccess)
+
+		var aggregateErr *mercury.AggregateError
+		require.ErrorAs(t, err, &aggregateErr)
+		require.Len(t, aggregateErr.Errors, 2)
+		require.Equal(t, "many-errors::v1", aggregateErr.Errors[0].Options["eventName"])
+		require.Equal(t, "not allowed", aggregateErr.Errors[1].FriendlyMessage)
+	})
+}
@@ -1,6 +1,8 @@
 package mercury
 
 import (
+	"context"
+	"log/slog"
 	"os"
 
 	spruce "github.com/sprucelabsai-community/spruce-core-schemas/v41/pkg/schemas"
@@ -10,9 +12,42 @@ type (
 	Factory struct{}
 
 	MercuryClientOptions struct {
-		TimeoutSec         int
-		Host               string
+		TimeoutSec int
+		// EmitTimeoutSec bounds emits made without a context deadline.
+		// Defaults to 30 seconds when it is zero or negative.
+		EmitTimeoutSec int
+		Host           string
+		// ShouldRetryConnect retries a failed connect and reconnects after the
+		// connection drops. When it is false the client fails on the first
+		// connect error and never reconnects. NewMercuryClient turns it on
+		// when it is called without options; callers passing options must set
+		// it themselves.
 		ShouldRetryConnect bool
+		// ReconnectPolicy tunes connect retries and reconnects. It is ignored
+		// when ShouldRetryConnect is false. Nil uses DefaultReconnectPolicy.
+		ReconnectPolicy *ReconnectPolicy
+		// Logger receives connection, emit and listener logs. Nothing is logged
+		// when it is nil.
+		Logger *slog.Logger
+		// LogPayloads includes targets and payloads in debug logs instead of
+		// redacting them.
+		LogPayloads bool
+		// Validator checks emits and responses against event signatures. No
+		// validation happens when it is nil.
+		Validator *Validator
+		// Metrics records emit, listener and connection metrics. Nothing is
+		// recorded when it is nil.
+		Metrics Metrics
+		// EmitRetry retries failed emits for the events it lists. Nothing is
+		// retried when it is nil.
+		EmitRetry *EmitRetryPolicy
+		// OfflineQueue holds emits while disconnected and sends them once the
+		// session is restored. Emits are sent straight away when it is nil.
+		OfflineQueue *OfflineQueueOptions
+		// TokenStore remembers the token of the last person to authenticate,
+		// and the client authenticates with it when it connects or
+		// reconnects. Tokens Mercury rejects are cleared.
+		TokenStore TokenStore
 	}
 
 	TargetAndPayload struct {
@@ -49,14 +84,32 @@ type (
 
 	MercuryListener = func(targetAndPayload TargetAndPayload) any
 
+	// ContextListener is a listener that gets a context and returns its error
+	// explicitly. See Client.OnContext.
+	ContextListener = func(ctx context.Context, targetAndPayload TargetAndPayload) (any, error)
+
 	MercuryClient interface {
 		Connect(url string, opts MercuryClientOptions) error
 		Disconnect()
 		IsConnected() bool
 		Emit(event string, targetAndPayload ...TargetAndPayload) ([]ResponsePayload, error)
+		EmitContext(ctx context.Context, event string, targetAndPayload ...TargetAndPayload) ([]ResponsePayload, error)
+		EmitAggregate(ctx context.Context, event string, targetAndPayload ...TargetAndPayload) (*AggregateResult, error)
 		Authenticate(opts AuthenticatePayload) (*AuthenticatResponse, error)
-		On(event string, listener MercuryListener)
-		Off(event string, listener ...MercuryListener)
+		RequestPin(ctx context.Context, phone string) (Challenge, error)
+		ConfirmPin(ctx context.Context, challenge Challenge, pin string) (*spruce.Person, string, error)
+		WhoAmI(ctx context.Context) (*Auth, error)
+		CurrentAuth() Auth
+		On(event string, listener MercuryListener, opts ...ListenerOptions) (*Subscription, error)
+		OnContext(event string, listener ContextListener, opts ...ListenerOptions) (*Subscription, error)
+		OnMany(listeners map[string]MercuryListener, opts ...ListenerOptions) (map[string]*Subscription, error)
+		OnManyContext(listeners map[string]ContextListener, opts ...ListenerOptions) (map[string]*Subscription, error)
+		Off(event string, subscriptions ...*Subscription) error
+		Use(middleware ...ListenerMiddleware)
+		Intercept(interceptors ...EmitInterceptor)
+		OnSessionRestored(cb func(err error))
+		OnConnectionStateChange(listener ConnectionStateListener)
+		State() ConnectionState
 	}
 )
 
@@ -96,9 +149,12 @@ func NewMercuryClient(opts ...MercuryClientOptions) (MercuryClient, error) {
 	return factory.Client(host, opts...)
 }
 
+const defaultEmitTimeoutSec = 30
+
 func defaultMercuryClientOptions() MercuryClientOptions {
 	return MercuryClientOptions{
 		TimeoutSec:         10,
+		EmitTimeoutSec:     defaultEmitTimeoutSec,
 		ShouldRetryConnect: true,
 	}
 }
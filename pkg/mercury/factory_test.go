@@ -27,13 +27,22 @@ func TestFactory(t *testing.T) {
 		require.True(t, opts.Reconnection(), "Reconnection should be true by default")
 	})
 
+	t.Run("fills in timeouts when only some options are set", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, _, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{Host: "http://waka-waka", ShouldRetryConnect: true})
+		require.NoError(t, err)
+		opts := fake.GetOptions()
+		require.True(t, opts.Reconnection(), "Reconnection should be true when set to true")
+		require.Equal(t, 10*time.Second, opts.Timeout())
+	})
+
 	t.Run("can set reconnect to false", func(t *testing.T) {
 		testkit.BeforeEachInternal(t)
 		fake, _, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{ShouldRetryConnect: false, Host: "http://waka-waka"})
 		require.NoError(t, err)
 		opts := fake.GetOptions()
 		require.NotNil(t, opts, "Options should not be nil")
-		require.True(t, opts.Reconnection(), "Reconnection should be false when set to false")
+		require.False(t, opts.Reconnection(), "Reconnection should be false when set to false")
 		require.Equal(t, "http://waka-waka", fake.GetHost(), "Host should be set to http://waka-waka")
 	})
 
@@ -0,0 +1,41 @@
+package mercury
+
+import (
+	"context"
+	"slices"
+)
+
+type (
+	// EmitHandler is an emit as seen by interceptors. Responder errors are on
+	// the result; the error is only set when the emit itself fails.
+	EmitHandler func(ctx context.Context, event string, targetAndPayload TargetAndPayload) (*AggregateResult, error)
+
+	// EmitInterceptor wraps every emit. It can change the event and target and
+	// payload before calling next, change what next returns, or skip next and
+	// return a result of its own.
+	EmitInterceptor func(next EmitHandler) EmitHandler
+)
+
+// Intercept adds interceptors that wrap every emit, including the ones the
+// client sends itself, like register-listeners. Interceptors run in the order
+// they were added, so the first interceptor is the outermost. Retries and
+// validation run after every interceptor, against what is actually sent.
+func (c *Client) Intercept(interceptors ...EmitInterceptor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
+func (c *Client) emitChain() EmitHandler {
+	handler := EmitHandler(c.emitWithRetry)
+
+	c.mu.Lock()
+	interceptors := slices.Clone(c.interceptors)
+	c.mu.Unlock()
+
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		handler = interceptors[i](handler)
+	}
+
+	return handler
+}
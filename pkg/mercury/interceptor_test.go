@@ -0,0 +1,145 @@
+package mercury_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptors(t *testing.T) {
+
+	t.Run("can change the event and target before it is sent", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.Intercept(func(next mercury.EmitHandler) mercury.EmitHandler {
+			return func(ctx context.Context, event string, targetAndPayload mercury.TargetAndPayload) (*mercury.AggregateResult, error) {
+				if event == "old-event::v1" {
+					event = "new-event::v1"
+				}
+				if targetAndPayload.Target == nil {
+					targetAndPayload.Target = map[string]any{}
+				}
+				targetAndPayload.Target["organizationId"] = "org-1"
+				return next(ctx, event, targetAndPayload)
+			}
+		})
+
+		var received mercury.TargetAndPayload
+		client.On("new-event::v1", func(targetAndPayload mercury.TargetAndPayload) any {
+			received = targetAndPayload
+			return nil
+		})
+
+		fake.ClearEmittedEvents()
+		_, err = client.Emit("old-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, "org-1", received.Target["organizationId"])
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Equal(t, "new-event::v1", emits[0].Event)
+	})
+
+	t.Run("can transform responses", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("an-event::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"hello": "world"}
+		})
+
+		client.Intercept(func(next mercury.EmitHandler) mercury.EmitHandler {
+			return func(ctx context.Context, event string, targetAndPayload mercury.TargetAndPayload) (*mercury.AggregateResult, error) {
+				result, err := next(ctx, event, targetAndPayload)
+				if err != nil {
+					return nil, err
+				}
+				for _, response := range result.Responses {
+					response.Payload["intercepted"] = true
+				}
+				return result, nil
+			}
+		})
+
+		responses, err := client.Emit("an-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"hello": "world", "intercepted": true}}, responses)
+	})
+
+	t.Run("can short-circuit with a cached result", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.Intercept(func(next mercury.EmitHandler) mercury.EmitHandler {
+			return func(ctx context.Context, event string, targetAndPayload mercury.TargetAndPayload) (*mercury.AggregateResult, error) {
+				return &mercury.AggregateResult{
+					Fqen:           event,
+					TotalResponses: 1,
+					Responses: []mercury.ResponderResult{{
+						Payload: mercury.ResponsePayload{"cached": true},
+					}},
+				}, nil
+			}
+		})
+
+		fake.ClearEmittedEvents()
+		responses, err := client.Emit("cached-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"cached": true}}, responses)
+		require.Empty(t, fake.EmittedEvents(), "Nothing should reach the socket")
+	})
+
+	t.Run("runs interceptors in the order they were added", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var calls []string
+		record := func(name string) mercury.EmitInterceptor {
+			return func(next mercury.EmitHandler) mercury.EmitHandler {
+				return func(ctx context.Context, event string, targetAndPayload mercury.TargetAndPayload) (*mercury.AggregateResult, error) {
+					calls = append(calls, name+":before")
+					result, err := next(ctx, event, targetAndPayload)
+					calls = append(calls, name+":after")
+					return result, err
+				}
+			}
+		}
+
+		client.On("an-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		client.Intercept(record("first"), record("second"))
+
+		_, err = client.Emit("an-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"first:before", "second:before", "second:after", "first:after"}, calls)
+	})
+
+	t.Run("can observe emit errors", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var seen error
+		client.Intercept(func(next mercury.EmitHandler) mercury.EmitHandler {
+			return func(ctx context.Context, event string, targetAndPayload mercury.TargetAndPayload) (*mercury.AggregateResult, error) {
+				result, err := next(ctx, event, targetAndPayload)
+				seen = err
+				return result, err
+			}
+		})
+
+		fake.MakeEventReturnError("broken-event::v1", errors.New("socket closed"))
+
+		_, err = client.Emit("broken-event::v1")
+		require.EqualError(t, err, "socket closed")
+		require.EqualError(t, seen, "socket closed")
+	})
+}
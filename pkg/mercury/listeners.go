@@ -0,0 +1,158 @@
+package mercury
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Subscription is one listener added with On.
+type Subscription struct {
+	client   *Client
+	event    string
+	listener ContextListener
+	pool     *listenerPool
+	timeout  time.Duration
+}
+
+type listenerResult struct {
+	response any
+	err      error
+}
+
+func (s *Subscription) Event() string {
+	return s.event
+}
+
+// Off removes just this listener from its event.
+func (s *Subscription) Off() error {
+	return s.client.Off(s.event, s)
+}
+
+// composeResponses turns what every listener on an event returned into one
+// ack. A single response is passed through untouched. Several responses are
+// shallow merged in the order the listeners were added, so later listeners
+// win when keys collide.
+func composeResponses(responses []any) any {
+	switch len(responses) {
+	case 0:
+		return nil
+	case 1:
+		return responses[0]
+	}
+
+	merged := map[string]any{}
+	for _, response := range responses {
+		values, ok := response.(map[string]any)
+		if !ok {
+			values = map[string]any{}
+			if err := mapToStruct(response, &values); err != nil {
+				continue
+			}
+		}
+
+		for key, value := range values {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
+
+func sendListenerAck(ack func([]any, error), event string, results []listenerResult) {
+	if ack == nil {
+		return
+	}
+
+	var errs []error
+	var responses []any
+
+	for _, result := range results {
+		if result.err != nil {
+			errs = append(errs, result.err)
+		} else if result.response != nil {
+			responses = append(responses, result.response)
+		}
+	}
+
+	if len(errs) > 0 {
+		ack([]any{listenerErrorAck(event, errs)}, nil)
+		return
+	}
+
+	if response := composeResponses(responses); response != nil {
+		ack([]any{response}, nil)
+		return
+	}
+
+	ack(nil, nil)
+}
+
+// listenerErrorAck builds the ack Mercury expects when listeners fail, with
+// one LISTENER_ERROR per failure. Listeners that had no room to run or ran out
+// of time get LISTENER_BUSY or LISTENER_TIMEOUT instead.
+func listenerErrorAck(event string, errs []error) map[string]any {
+	ackErrs := make([]any, len(errs))
+	for i, err := range errs {
+		code := ErrorCodeListenerError
+		message := err.Error()
+
+		var spruceErr *SpruceError
+		if errors.As(err, &spruceErr) && (spruceErr.Code == ErrorCodeListenerBusy || spruceErr.Code == ErrorCodeListenerTimeout) {
+			code = spruceErr.Code
+			message = spruceErr.FriendlyMessage
+		}
+
+		ackErrs[i] = map[string]any{
+			"code":            code,
+			"friendlyMessage": message,
+			"fqen":            event,
+			"originalError":   message,
+		}
+	}
+
+	return map[string]any{
+		"errors": ackErrs,
+	}
+}
+
+func listenerBusyError(event string) error {
+	return &SpruceError{
+		Code:            ErrorCodeListenerBusy,
+		FriendlyMessage: fmt.Sprintf("'%s' is busy, try again later", event),
+		Fqen:            event,
+	}
+}
+
+func listenerTimeoutError(event string, timeout time.Duration) error {
+	return &SpruceError{
+		Code:            ErrorCodeListenerTimeout,
+		FriendlyMessage: fmt.Sprintf("'%s' did not finish within %s", event, timeout),
+		Fqen:            event,
+	}
+}
+
+// listenerContext returns the context listeners run under until the next
+// disconnect.
+func (c *Client) listenerContext() context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.listenerCtx == nil {
+		c.listenerCtx, c.cancelListenerCtx = context.WithCancel(context.Background())
+	}
+	return c.listenerCtx
+}
+
+// cancelListeners cancels the context of every running listener. Listeners
+// invoked after this get a fresh context.
+func (c *Client) cancelListeners() {
+	c.mu.Lock()
+	cancel := c.cancelListenerCtx
+	c.listenerCtx, c.cancelListenerCtx = nil, nil
+	c.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
@@ -0,0 +1,303 @@
+package mercury_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListeners(t *testing.T) {
+
+	t.Run("registers the event once for several listeners", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.ClearEmittedEvents()
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Equal(t, []string{"shared-event::v1"}, registeredEventNames(emits[0]))
+	})
+
+	t.Run("merges responses with later listeners winning", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"first": true, "winner": "first"}
+		})
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"second": true, "winner": "second"}
+		})
+
+		responses, err := client.Emit("shared-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{
+			"first":  true,
+			"second": true,
+			"winner": "second",
+		}}, responses)
+	})
+
+	t.Run("reports an error for every failing listener", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		wasHit := false
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return errors.New("first failed") })
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any {
+			wasHit = true
+			return nil
+		})
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return errors.New("second failed") })
+
+		_, err = client.Emit("shared-event::v1")
+		require.True(t, wasHit, "Healthy listeners should still run")
+
+		var aggregateErr *mercury.AggregateError
+		require.ErrorAs(t, err, &aggregateErr)
+		require.Len(t, aggregateErr.Errors, 2)
+		require.ErrorContains(t, err, "first failed")
+		require.ErrorContains(t, err, "second failed")
+	})
+
+	t.Run("turns off a single listener", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		firstHits, secondHits := 0, 0
+		first, err := client.On("shared-event::v1", func(mercury.TargetAndPayload) any {
+			firstHits++
+			return nil
+		})
+		require.NoError(t, err)
+		second, err := client.On("shared-event::v1", func(mercury.TargetAndPayload) any {
+			secondHits++
+			return nil
+		})
+		require.NoError(t, err)
+
+		fake.ClearEmittedEvents()
+		require.NoError(t, first.Off())
+
+		_, err = client.Emit("shared-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, 0, firstHits)
+		require.Equal(t, 1, secondHits)
+		require.Equal(t, "shared-event::v1", second.Event())
+		require.False(t, hasEmitted(fake, "unregister-listeners::v2020_12_25"), "Should keep listening while a listener is left")
+	})
+
+	t.Run("unregisters once the last listener is off", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		first, err := client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		require.NoError(t, err)
+		second, err := client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		require.NoError(t, err)
+
+		fake.ClearEmittedEvents()
+		require.NoError(t, first.Off())
+		require.NoError(t, second.Off())
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Equal(t, "unregister-listeners::v2020_12_25", emits[0].Event)
+		require.Equal(t, []string{"shared-event::v1"}, emits[0].TargetAndPayload.Payload["fullyQualifiedEventNames"])
+
+		_, err = client.Emit("shared-event::v1")
+		require.Error(t, err, "No listeners should be left")
+	})
+
+	t.Run("replays events that still have listeners after reconnect", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		first, err := client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		require.NoError(t, err)
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		require.NoError(t, first.Off())
+
+		restored := waitForRestore(client)
+		fake.ClearEmittedEvents()
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Equal(t, []string{"shared-event::v1"}, registeredEventNames(emits[0]))
+	})
+
+	t.Run("returns the error when registering fails", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.MakeEventFailTimes("register-listeners::v2020_12_25", 1, errors.New("transport closed"))
+
+		subscription, err := client.On("refused-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		require.Nil(t, subscription)
+		require.ErrorContains(t, err, "failed to register listeners for refused-event::v1: transport closed")
+
+		_, err = client.Emit("refused-event::v1")
+		require.Error(t, err, "The listener should not be left behind")
+
+		_, err = client.On("refused-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		require.NoError(t, err, "Adding the listener again should register it again")
+	})
+
+	t.Run("fails listeners added while the registration is in flight", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		var once sync.Once
+		fake.On("register-listeners::v2020_12_25", func(args ...any) {
+			callback := testkit.PluckCallback(args)
+			failed := false
+			once.Do(func() {
+				close(entered)
+				<-release
+				failed = true
+			})
+			if failed {
+				callback(nil, errors.New("boom"))
+				return
+			}
+			callback([]any{}, nil)
+		})
+		fake.ClearEmittedEvents()
+
+		first := make(chan error, 1)
+		go func() {
+			_, err := client.On("acme.e::v1", func(mercury.TargetAndPayload) any { return nil })
+			first <- err
+		}()
+		receiveSignal(t, entered)
+
+		second := make(chan error, 1)
+		go func() {
+			_, err := client.On("acme.e::v1", func(mercury.TargetAndPayload) any { return nil })
+			second <- err
+		}()
+
+		select {
+		case err := <-second:
+			t.Fatalf("Second listener should wait for the registration, finished with %v", err)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(release)
+		require.ErrorContains(t, receiveErr(t, first), "failed to register listeners for acme.e::v1: boom")
+		require.ErrorContains(t, receiveErr(t, second), "failed to register listeners for acme.e::v1: boom")
+
+		_, err = client.Emit("acme.e::v1")
+		require.Error(t, err, "Neither listener should be left behind")
+
+		_, err = client.On("acme.e::v1", func(mercury.TargetAndPayload) any { return nil })
+		require.NoError(t, err, "Adding the listener again should register it again")
+
+		registrations := 0
+		for _, emit := range fake.EmittedEvents() {
+			if emit.Event == "register-listeners::v2020_12_25" {
+				registrations++
+			}
+		}
+		require.Equal(t, 2, registrations)
+	})
+
+	t.Run("returns the error when unregistering fails", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("shared-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		fake.MakeEventFailTimes("unregister-listeners::v2020_12_25", 1, errors.New("transport closed"))
+
+		err = client.Off("shared-event::v1")
+		require.ErrorContains(t, err, "failed to unregister listeners for shared-event::v1: transport closed")
+	})
+
+	t.Run("registers many listeners in one emit", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("existing-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		fake.ClearEmittedEvents()
+
+		subscriptions, err := client.OnMany(map[string]mercury.MercuryListener{
+			"second-event::v1":   func(mercury.TargetAndPayload) any { return map[string]any{"second": true} },
+			"first-event::v1":    func(mercury.TargetAndPayload) any { return map[string]any{"first": true} },
+			"existing-event::v1": func(mercury.TargetAndPayload) any { return nil },
+		})
+		require.NoError(t, err)
+		require.Len(t, subscriptions, 3)
+		require.Equal(t, "first-event::v1", subscriptions["first-event::v1"].Event())
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Equal(t, []string{"first-event::v1", "second-event::v1"}, registeredEventNames(emits[0]))
+
+		responses, err := client.Emit("second-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"second": true}}, responses)
+	})
+
+	t.Run("adds none of many listeners when registering fails", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.MakeEventFailTimes("register-listeners::v2020_12_25", 1, errors.New("transport closed"))
+
+		subscriptions, err := client.OnMany(map[string]mercury.MercuryListener{
+			"first-event::v1":  func(mercury.TargetAndPayload) any { return nil },
+			"second-event::v1": func(mercury.TargetAndPayload) any { return nil },
+		})
+		require.Nil(t, subscriptions)
+		require.ErrorContains(t, err, "first-event::v1, second-event::v1")
+
+		_, err = client.Emit("first-event::v1")
+		require.Error(t, err)
+		_, err = client.Emit("second-event::v1")
+		require.Error(t, err)
+	})
+}
+
+func hasEmitted(fake *testkit.FakeSocketClient, event string) bool {
+	for _, emit := range fake.EmittedEvents() {
+		if emit.Event == event {
+			return true
+		}
+	}
+	return false
+}
+
+func receiveErr(t *testing.T, errs <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-errs:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for On")
+		return nil
+	}
+}
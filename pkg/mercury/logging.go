@@ -0,0 +1,26 @@
+package mercury
+
+import "log/slog"
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+func (c *Client) log() *slog.Logger {
+	if c.logger == nil {
+		return discardLogger
+	}
+	return c.logger
+}
+
+func (c *Client) payloadAttr(targetAndPayload TargetAndPayload) slog.Attr {
+	if !c.logPayloads {
+		return slog.String("targetAndPayload", "[redacted]")
+	}
+	return slog.Any("targetAndPayload", targetAndPayload)
+}
+
+func firstArg(args []any) any {
+	if len(args) == 0 {
+		return nil
+	}
+	return args[0]
+}
@@ -0,0 +1,96 @@
+package mercury_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogging(t *testing.T) {
+
+	t.Run("logs connect and emits with redacted payloads", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		logs, logger := makeTestLogger()
+		_, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{Logger: logger})
+		require.NoError(t, err)
+
+		client.On("log-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		_, err = client.Emit("log-event::v1", mercury.TargetAndPayload{
+			Payload: map[string]any{"secret": "shhh"},
+		})
+		require.NoError(t, err)
+
+		output := logs.String()
+		require.Contains(t, output, `"msg":"Connected to Mercury"`)
+		require.Contains(t, output, `"msg":"Emitting","event":"log-event::v1"`)
+		require.Contains(t, output, `"msg":"Emit finished","event":"log-event::v1","duration"`)
+		require.Contains(t, output, `"msg":"Listener invoked","event":"log-event::v1"`)
+		require.Contains(t, output, `[redacted]`)
+		require.NotContains(t, output, "shhh")
+	})
+
+	t.Run("logs the default emit timeout of clients connected without one", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		mercury.SetConnect(testkit.FakeSocketConnect)
+		logs, logger := makeTestLogger()
+
+		client := &mercury.Client{}
+		require.NoError(t, client.Connect("https://mercury.test", mercury.MercuryClientOptions{Logger: logger}))
+
+		_, err := client.On("log-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		require.NoError(t, err)
+		_, err = client.Emit("log-event::v1")
+		require.NoError(t, err)
+
+		require.Contains(t, logs.String(), `"timeout":30000000000`)
+	})
+
+	t.Run("logs payloads when enabled", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		logs, logger := makeTestLogger()
+		_, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{Logger: logger, LogPayloads: true})
+		require.NoError(t, err)
+
+		client.On("log-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		_, err = client.Emit("log-event::v1", mercury.TargetAndPayload{
+			Payload: map[string]any{"secret": "shhh"},
+		})
+		require.NoError(t, err)
+		require.Contains(t, logs.String(), "shhh")
+	})
+
+	t.Run("logs listener panics and still acks with an error", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		logs, logger := makeTestLogger()
+		_, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{Logger: logger})
+		require.NoError(t, err)
+
+		client.On("panics::v1", func(mercury.TargetAndPayload) any {
+			panic("kaboom")
+		})
+
+		_, err = client.Emit("panics::v1")
+		require.ErrorIs(t, err, mercury.ErrListenerError)
+		require.ErrorContains(t, err, "listener panic: kaboom")
+		require.Contains(t, logs.String(), `"level":"ERROR","msg":"Listener panicked","event":"panics::v1","panic":"kaboom"`)
+	})
+
+	t.Run("logs disconnects as warnings", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		logs, logger := makeTestLogger()
+		fake, _, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{Logger: logger})
+		require.NoError(t, err)
+
+		fake.SimulateDisconnect()
+		require.Contains(t, logs.String(), `"level":"WARN","msg":"Disconnected from Mercury"`)
+	})
+}
+
+func makeTestLogger() (*bytes.Buffer, *slog.Logger) {
+	logs := &bytes.Buffer{}
+	return logs, slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
@@ -0,0 +1,104 @@
+package mercury
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	spruce "github.com/sprucelabsai-community/spruce-core-schemas/v41/pkg/schemas"
+)
+
+// Challenge identifies a pin sent by RequestPin. Pass it to ConfirmPin along
+// with the pin the person received.
+type Challenge struct {
+	Id    string
+	Phone string
+}
+
+// RequestPin texts a login pin to phone.
+func (c *Client) RequestPin(ctx context.Context, phone string) (Challenge, error) {
+	if !isPhoneNumber(phone) {
+		return Challenge{}, &SpruceError{
+			Code:            ErrorCodeInvalidPhone,
+			FriendlyMessage: fmt.Sprintf("'%s' is not a valid phone number", phone),
+			Fqen:            "request-pin::v2020_12_25",
+		}
+	}
+
+	results, err := c.EmitContext(ctx, "request-pin::v2020_12_25", TargetAndPayload{
+		Payload: map[string]any{
+			"phone": phone,
+		},
+	})
+	if err != nil {
+		return Challenge{}, err
+	}
+
+	if len(results) == 0 {
+		return Challenge{}, fmt.Errorf("request-pin returned no responses")
+	}
+
+	id, ok := results[0]["challenge"].(string)
+	if !ok || id == "" {
+		return Challenge{}, fmt.Errorf("challenge field not found in response")
+	}
+
+	return Challenge{Id: id, Phone: phone}, nil
+}
+
+// ConfirmPin logs in the person the challenge was sent to. On success the
+// client is authenticated as that person, and stays so across reconnects, so
+// there is no need to call Authenticate with the returned token.
+func (c *Client) ConfirmPin(ctx context.Context, challenge Challenge, pin string) (*spruce.Person, string, error) {
+	results, err := c.EmitContext(ctx, "confirm-pin::v2020_12_25", TargetAndPayload{
+		Payload: map[string]any{
+			"challenge": challenge.Id,
+			"pin":       pin,
+		},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(results) == 0 {
+		return nil, "", fmt.Errorf("confirm-pin returned no responses")
+	}
+
+	token, ok := results[0]["token"].(string)
+	if !ok || token == "" {
+		return nil, "", fmt.Errorf("token field not found in response")
+	}
+
+	personValues, ok := results[0]["person"].(map[string]any)
+	if !ok {
+		return nil, "", fmt.Errorf("person field not found in response")
+	}
+
+	auth := Auth{Type: AuthTypeAuthenticated}
+	if err := auth.parse(map[string]any{"person": personValues}); err != nil {
+		return nil, "", err
+	}
+
+	c.rememberAuth(AuthenticatePayload{Token: token})
+	c.setAuth(auth)
+	c.storeToken(token)
+
+	return auth.Person, token, nil
+}
+
+// isPhoneNumber does a loose check so obvious typos fail before reaching
+// Mercury, which does the real validation.
+func isPhoneNumber(phone string) bool {
+	digits := 0
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' && i == 0:
+		case strings.ContainsRune(" -.()", r):
+		default:
+			return false
+		}
+	}
+	return digits >= 10 && digits <= 15
+}
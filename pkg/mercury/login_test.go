@@ -0,0 +1,100 @@
+package mercury_test
+
+import (
+	"context"
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogin(t *testing.T) {
+
+	t.Run("requests a pin", func(t *testing.T) {
+		fake, client := makeLoginClient(t, nil)
+
+		challenge, err := client.RequestPin(context.Background(), "+1 555-555-5555")
+		require.NoError(t, err)
+		require.Equal(t, mercury.Challenge{Id: "challenge-1", Phone: "+1 555-555-5555"}, challenge)
+
+		emits := fake.EmittedEvents()
+		require.Equal(t, "request-pin::v2020_12_25", emits[len(emits)-1].Event)
+		require.Equal(t, "+1 555-555-5555", emits[len(emits)-1].TargetAndPayload.Payload["phone"])
+	})
+
+	t.Run("rejects an invalid phone before emitting", func(t *testing.T) {
+		fake, client := makeLoginClient(t, nil)
+		fake.ClearEmittedEvents()
+
+		_, err := client.RequestPin(context.Background(), "555-CALL-NOW")
+		require.ErrorIs(t, err, mercury.ErrInvalidPhone)
+		require.Empty(t, fake.EmittedEvents())
+	})
+
+	t.Run("passes back an invalid phone from Mercury", func(t *testing.T) {
+		fake, client := makeLoginClient(t, nil)
+		fake.MakeEventReturnResponses("request-pin::v2020_12_25", []mercury.ResponsePayload{
+			{"errors": []any{map[string]any{"code": mercury.ErrorCodeInvalidPhone}}},
+		})
+
+		_, err := client.RequestPin(context.Background(), "+1 555-555-5555")
+		require.ErrorIs(t, err, mercury.ErrInvalidPhone)
+	})
+
+	t.Run("confirms the pin and authenticates as the person", func(t *testing.T) {
+		store := &mercury.MemoryTokenStore{}
+		fake, client := makeLoginClient(t, store)
+
+		challenge, err := client.RequestPin(context.Background(), "+1 555-555-5555")
+		require.NoError(t, err)
+
+		person, token, err := client.ConfirmPin(context.Background(), challenge, "0000")
+		require.NoError(t, err)
+		require.Equal(t, "person-1", person.Id)
+		require.Equal(t, "token-1", token)
+		requireStoredToken(t, store, "token-1")
+
+		confirm := fake.EmittedEvents()[len(fake.EmittedEvents())-1]
+		require.Equal(t, "challenge-1", confirm.TargetAndPayload.Payload["challenge"])
+		require.Equal(t, "0000", confirm.TargetAndPayload.Payload["pin"])
+
+		fakeAuthenticate(fake)
+		restored := waitForRestore(client)
+		fake.ClearEmittedEvents()
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Equal(t, "authenticate::v2020_12_25", emits[0].Event)
+		require.Equal(t, "token-1", emits[0].TargetAndPayload.Payload["token"])
+	})
+
+	t.Run("returns typed errors for a wrong pin or expired challenge", func(t *testing.T) {
+		for _, code := range []string{mercury.ErrorCodeInvalidPin, mercury.ErrorCodeChallengeExpired} {
+			fake, client := makeLoginClient(t, nil)
+			fake.MakeEventReturnResponses("confirm-pin::v2020_12_25", []mercury.ResponsePayload{
+				{"errors": []any{map[string]any{"code": code}}},
+			})
+
+			_, _, err := client.ConfirmPin(context.Background(), mercury.Challenge{Id: "challenge-1"}, "1234")
+			require.True(t, mercury.HasCode(err, code), "Expected %s, got %v", code, err)
+		}
+	})
+}
+
+func makeLoginClient(t *testing.T, store mercury.TokenStore) (*testkit.FakeSocketClient, mercury.MercuryClient) {
+	testkit.BeforeEachInternal(t)
+	fake, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{TokenStore: store})
+	require.NoError(t, err)
+
+	fake.MakeEventReturnResponses("request-pin::v2020_12_25", []mercury.ResponsePayload{
+		{"challenge": "challenge-1"},
+	})
+	fake.MakeEventReturnResponses("confirm-pin::v2020_12_25", []mercury.ResponsePayload{
+		{"token": "token-1", "person": map[string]any{"id": "person-1", "casualName": "friend"}},
+	})
+
+	return fake, client
+}
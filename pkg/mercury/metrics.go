@@ -0,0 +1,30 @@
+package mercury
+
+import "time"
+
+// Metrics receives measurements from the client. Implementations must be safe
+// for concurrent use. See the prommercury package for a Prometheus adapter.
+type Metrics interface {
+	// EmitFinished runs after every emit. Result is nil when err is set.
+	EmitFinished(event string, duration time.Duration, result *AggregateResult, err error)
+	// ListenerFinished runs after every listener, including ones that panicked.
+	ListenerFinished(event string, duration time.Duration, err error)
+	ListenerPanicked(event string)
+	Reconnected()
+	ConnectionStateChanged(state ConnectionState)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) EmitFinished(string, time.Duration, *AggregateResult, error) {}
+func (noopMetrics) ListenerFinished(string, time.Duration, error)               {}
+func (noopMetrics) ListenerPanicked(string)                                     {}
+func (noopMetrics) Reconnected()                                                {}
+func (noopMetrics) ConnectionStateChanged(ConnectionState)                      {}
+
+func (c *Client) meter() Metrics {
+	if c.metrics == nil {
+		return noopMetrics{}
+	}
+	return c.metrics
+}
@@ -0,0 +1,41 @@
+package mercury
+
+import (
+	"context"
+	"slices"
+)
+
+type (
+	// ListenerHandler is a listener as seen by middleware. Returning an error
+	// sends a LISTENER_ERROR back to the emitter.
+	ListenerHandler func(ctx context.Context, event string, targetAndPayload TargetAndPayload) (any, error)
+
+	// ListenerMiddleware wraps a handler. Call next to continue the chain, or
+	// return without calling it to short-circuit.
+	ListenerMiddleware func(next ListenerHandler) ListenerHandler
+)
+
+// Use adds middleware that wraps every listener added with On, including
+// listeners added before Use was called. Middleware runs in the order it was
+// added, so the first middleware is the outermost.
+func (c *Client) Use(middleware ...ListenerMiddleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.middleware = append(c.middleware, middleware...)
+}
+
+func (c *Client) listenerChain(listener ContextListener) ListenerHandler {
+	handler := ListenerHandler(func(ctx context.Context, event string, targetAndPayload TargetAndPayload) (any, error) {
+		return listener(ctx, targetAndPayload)
+	})
+
+	c.mu.Lock()
+	middleware := slices.Clone(c.middleware)
+	c.mu.Unlock()
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,136 @@
+package mercury_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	t.Run("runs middleware in the order it was added", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var calls []string
+		client.Use(recordCalls(&calls, "first"), recordCalls(&calls, "second"))
+
+		client.On("guarded-event::v1", func(mercury.TargetAndPayload) any {
+			calls = append(calls, "listener")
+			return nil
+		})
+
+		_, err = client.Emit("guarded-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"first:before", "second:before", "listener", "second:after", "first:after"}, calls)
+	})
+
+	t.Run("wraps listeners added before Use", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("guarded-event::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"hello": "world"}
+		})
+
+		client.Use(func(next mercury.ListenerHandler) mercury.ListenerHandler {
+			return func(ctx context.Context, event string, targetAndPayload mercury.TargetAndPayload) (any, error) {
+				response, err := next(ctx, event, targetAndPayload)
+				if err != nil {
+					return nil, err
+				}
+				values := response.(map[string]any)
+				values["event"] = event
+				return values, nil
+			}
+		})
+
+		responses, err := client.Emit("guarded-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"hello": "world", "event": "guarded-event::v1"}}, responses)
+	})
+
+	t.Run("short-circuits with a listener error", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.Use(func(next mercury.ListenerHandler) mercury.ListenerHandler {
+			return func(ctx context.Context, event string, targetAndPayload mercury.TargetAndPayload) (any, error) {
+				if targetAndPayload.Source["personId"] == nil {
+					return nil, errors.New("must be logged in")
+				}
+				return next(ctx, event, targetAndPayload)
+			}
+		})
+
+		wasHit := false
+		client.On("guarded-event::v1", func(mercury.TargetAndPayload) any {
+			wasHit = true
+			return nil
+		})
+
+		_, err = client.Emit("guarded-event::v1")
+		require.False(t, wasHit, "Listener should not run when middleware short-circuits")
+		require.ErrorIs(t, err, mercury.ErrListenerError)
+		require.ErrorContains(t, err, "must be logged in")
+	})
+
+	t.Run("passes errors returned by listeners through the chain", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var seen error
+		client.Use(func(next mercury.ListenerHandler) mercury.ListenerHandler {
+			return func(ctx context.Context, event string, targetAndPayload mercury.TargetAndPayload) (any, error) {
+				response, err := next(ctx, event, targetAndPayload)
+				seen = err
+				return response, err
+			}
+		})
+
+		client.On("guarded-event::v1", func(mercury.TargetAndPayload) any {
+			return errors.New("nope")
+		})
+
+		_, err = client.Emit("guarded-event::v1")
+		require.ErrorContains(t, err, "nope")
+		require.EqualError(t, seen, "nope")
+	})
+
+	t.Run("recovers from middleware panics", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.Use(func(next mercury.ListenerHandler) mercury.ListenerHandler {
+			return func(context.Context, string, mercury.TargetAndPayload) (any, error) {
+				panic("middleware kaboom")
+			}
+		})
+
+		client.On("guarded-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		_, err = client.Emit("guarded-event::v1")
+		require.ErrorIs(t, err, mercury.ErrListenerError)
+		require.ErrorContains(t, err, "listener panic: middleware kaboom")
+	})
+}
+
+func recordCalls(calls *[]string, name string) mercury.ListenerMiddleware {
+	return func(next mercury.ListenerHandler) mercury.ListenerHandler {
+		return func(ctx context.Context, event string, targetAndPayload mercury.TargetAndPayload) (any, error) {
+			*calls = append(*calls, name+":before")
+			response, err := next(ctx, event, targetAndPayload)
+			*calls = append(*calls, name+":after")
+			return response, err
+		}
+	}
+}
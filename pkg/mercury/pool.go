@@ -0,0 +1,78 @@
+package mercury
+
+import (
+	"sync"
+	"time"
+)
+
+// ListenerOptions limits how a listener added with On runs. The zero value
+// runs the listener on the goroutine the event arrives on, with no limit.
+type ListenerOptions struct {
+	// MaxConcurrent caps how many invocations run at once. 1 handles events
+	// one at a time, in the order they arrived.
+	MaxConcurrent int
+	// QueueSize caps how many events wait for a free worker. Events that do
+	// not fit are answered with a LISTENER_BUSY error.
+	QueueSize int
+	// Timeout bounds each invocation. The listener's context is cancelled and
+	// the emitter gets a LISTENER_TIMEOUT error once it passes. A listener
+	// still counts against MaxConcurrent until it actually returns.
+	Timeout time.Duration
+}
+
+func (o ListenerOptions) isPooled() bool {
+	return o.MaxConcurrent > 0 || o.QueueSize > 0
+}
+
+type listenerPool struct {
+	mu     sync.RWMutex
+	jobs   chan func()
+	closed bool
+}
+
+func newListenerPool(opts ListenerOptions) *listenerPool {
+	pool := &listenerPool{
+		jobs: make(chan func(), max(opts.QueueSize, 0)),
+	}
+
+	for range max(opts.MaxConcurrent, 1) {
+		go pool.work()
+	}
+
+	return pool
+}
+
+func (p *listenerPool) work() {
+	for job := range p.jobs {
+		job()
+	}
+}
+
+// submit queues job without blocking. It returns false when every worker is
+// busy and the queue is full.
+func (p *listenerPool) submit(job func()) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return false
+	}
+
+	select {
+	case p.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop lets queued jobs finish, then ends the workers.
+func (p *listenerPool) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+}
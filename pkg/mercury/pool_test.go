@@ -0,0 +1,225 @@
+package mercury_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenerPool(t *testing.T) {
+
+	t.Run("caps concurrent invocations", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var active, peak atomic.Int32
+		release := make(chan struct{})
+		started := make(chan struct{}, 10)
+
+		client.On("heavy-event::v1", func(mercury.TargetAndPayload) any {
+			now := active.Add(1)
+			for {
+				old := peak.Load()
+				if now <= old || peak.CompareAndSwap(old, now) {
+					break
+				}
+			}
+			started <- struct{}{}
+			<-release
+			active.Add(-1)
+			return nil
+		}, mercury.ListenerOptions{MaxConcurrent: 2, QueueSize: 10})
+
+		errs := make(chan error, 6)
+		var wg sync.WaitGroup
+		for range 6 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.Emit("heavy-event::v1")
+				errs <- err
+			}()
+		}
+
+		receiveSignal(t, started)
+		receiveSignal(t, started)
+
+		select {
+		case <-started:
+			t.Fatal("Only two listeners should run at once")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(release)
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		require.Equal(t, int32(2), peak.Load())
+	})
+
+	t.Run("keeps a worker busy until a timed out listener returns", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var active, peak atomic.Int32
+		release := make(chan struct{})
+		started := make(chan struct{}, 20)
+
+		_, err = client.OnContext("stuck-event::v1", func(context.Context, mercury.TargetAndPayload) (any, error) {
+			now := active.Add(1)
+			for {
+				old := peak.Load()
+				if now <= old || peak.CompareAndSwap(old, now) {
+					break
+				}
+			}
+			started <- struct{}{}
+			<-release
+			active.Add(-1)
+			return nil, nil
+		}, mercury.ListenerOptions{MaxConcurrent: 1, QueueSize: 1, Timeout: 20 * time.Millisecond})
+		require.NoError(t, err)
+
+		_, err = client.Emit("stuck-event::v1")
+		require.ErrorIs(t, err, mercury.ErrListenerTimeout, "Emitter should not wait for the stuck listener")
+		receiveSignal(t, started)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = client.Emit("stuck-event::v1")
+			}()
+		}
+
+		select {
+		case <-started:
+			t.Fatal("Timed out listener should keep its worker")
+		case <-time.After(60 * time.Millisecond):
+		}
+
+		close(release)
+		wg.Wait()
+		require.Equal(t, int32(1), peak.Load())
+	})
+
+	t.Run("handles events one at a time in order", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var mu sync.Mutex
+		var order []any
+		done := make(chan struct{}, 10)
+
+		client.On("ordered-event::v1", func(targetAndPayload mercury.TargetAndPayload) any {
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			order = append(order, targetAndPayload.Payload["n"])
+			mu.Unlock()
+			done <- struct{}{}
+			return nil
+		}, mercury.ListenerOptions{MaxConcurrent: 1, QueueSize: 10})
+
+		for n := range 10 {
+			fake.Trigger("ordered-event::v1", mercury.TargetAndPayload{Payload: map[string]any{"n": n}})
+		}
+
+		for range 10 {
+			receiveSignal(t, done)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, []any{0.0, 1.0, 2.0, 3.0, 4.0, 5.0, 6.0, 7.0, 8.0, 9.0}, order)
+	})
+
+	t.Run("answers with a busy error when the queue is full", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		started := make(chan struct{}, 2)
+
+		client.On("heavy-event::v1", func(mercury.TargetAndPayload) any {
+			started <- struct{}{}
+			<-release
+			return nil
+		}, mercury.ListenerOptions{MaxConcurrent: 1, QueueSize: 1})
+
+		fake.Trigger("heavy-event::v1")
+		receiveSignal(t, started)
+		fake.Trigger("heavy-event::v1")
+
+		_, err = client.Emit("heavy-event::v1")
+		require.ErrorIs(t, err, mercury.ErrListenerBusy)
+		require.ErrorContains(t, err, "'heavy-event::v1' is busy")
+
+		close(release)
+		receiveSignal(t, started)
+	})
+
+	t.Run("composes responses with listeners that run inline", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("mixed-event::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"pooled": true}
+		}, mercury.ListenerOptions{MaxConcurrent: 1, QueueSize: 1})
+
+		client.On("mixed-event::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"inline": true}
+		})
+
+		responses, err := client.Emit("mixed-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"pooled": true, "inline": true}}, responses)
+	})
+
+	t.Run("finishes queued events after Off", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		handled := make(chan struct{}, 2)
+
+		subscription, err := client.On("heavy-event::v1", func(mercury.TargetAndPayload) any {
+			<-release
+			handled <- struct{}{}
+			return nil
+		}, mercury.ListenerOptions{MaxConcurrent: 1, QueueSize: 2})
+		require.NoError(t, err)
+
+		fake.Trigger("heavy-event::v1")
+		fake.Trigger("heavy-event::v1")
+		require.NoError(t, subscription.Off())
+		close(release)
+
+		receiveSignal(t, handled)
+		receiveSignal(t, handled)
+	})
+}
+
+func receiveSignal(t *testing.T, signal <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-signal:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for listener")
+	}
+}
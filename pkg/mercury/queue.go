@@ -0,0 +1,171 @@
+package mercury
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// OverflowPolicy decides what happens to an emit when the offline queue is
+// full.
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest fails the oldest queued emit to make room.
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowDropNewest fails the emit that did not fit.
+	OverflowDropNewest
+	// OverflowBlock waits for room until the emit's context is done.
+	OverflowBlock
+)
+
+// ErrQueueFull is returned for emits dropped from a full offline queue.
+var ErrQueueFull = errors.New("offline emit queue is full")
+
+type OfflineQueueOptions struct {
+	// Size caps how many emits wait while disconnected. Defaults to 100.
+	Size     int
+	Overflow OverflowPolicy
+}
+
+type (
+	offlineQueue struct {
+		mu        sync.Mutex
+		size      int
+		overflow  OverflowPolicy
+		connected bool
+		flushing  bool
+		items     []*queuedEmit
+		space     chan struct{}
+	}
+
+	queuedEmit struct {
+		send   func() error
+		result chan error
+	}
+)
+
+func newOfflineQueue(opts OfflineQueueOptions) *offlineQueue {
+	size := opts.Size
+	if size <= 0 {
+		size = 100
+	}
+
+	return &offlineQueue{
+		size:      size,
+		overflow:  opts.Overflow,
+		connected: true,
+	}
+}
+
+// send calls send straight away while connected, otherwise it queues it and
+// waits until it is flushed, dropped or ctx is done.
+func (q *offlineQueue) send(ctx context.Context, send func() error) error {
+	item := &queuedEmit{send: send, result: make(chan error, 1)}
+
+	q.mu.Lock()
+	for {
+		if q.connected && !q.flushing && len(q.items) == 0 {
+			q.mu.Unlock()
+			return send()
+		}
+
+		if len(q.items) < q.size {
+			break
+		}
+
+		switch q.overflow {
+		case OverflowDropNewest:
+			q.mu.Unlock()
+			return ErrQueueFull
+		case OverflowDropOldest:
+			oldest := q.items[0]
+			q.items = q.items[1:]
+			oldest.result <- ErrQueueFull
+		case OverflowBlock:
+			space := q.spaceFreed()
+			q.mu.Unlock()
+			select {
+			case <-space:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			q.mu.Lock()
+		}
+	}
+
+	q.items = append(q.items, item)
+	q.mu.Unlock()
+
+	select {
+	case err := <-item.result:
+		return err
+	case <-ctx.Done():
+		if q.remove(item) {
+			return ctx.Err()
+		}
+		// The item was already flushed, so its send is underway.
+		return <-item.result
+	}
+}
+
+func (q *offlineQueue) setOffline() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.connected = false
+}
+
+// flush sends queued emits in order until the queue is empty or the
+// connection drops again.
+func (q *offlineQueue) flush() {
+	q.mu.Lock()
+	q.connected = true
+	q.flushing = true
+	q.mu.Unlock()
+
+	for {
+		q.mu.Lock()
+		if !q.connected || len(q.items) == 0 {
+			q.flushing = false
+			q.mu.Unlock()
+			return
+		}
+
+		item := q.items[0]
+		q.items = q.items[1:]
+		q.notifySpace()
+		q.mu.Unlock()
+
+		item.result <- item.send()
+	}
+}
+
+func (q *offlineQueue) remove(item *queuedEmit) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	index := slices.Index(q.items, item)
+	if index < 0 {
+		return false
+	}
+
+	q.items = slices.Delete(q.items, index, index+1)
+	q.notifySpace()
+
+	return true
+}
+
+func (q *offlineQueue) spaceFreed() <-chan struct{} {
+	if q.space == nil {
+		q.space = make(chan struct{})
+	}
+	return q.space
+}
+
+func (q *offlineQueue) notifySpace() {
+	if q.space != nil {
+		close(q.space)
+		q.space = nil
+	}
+}
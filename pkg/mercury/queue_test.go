@@ -0,0 +1,160 @@
+package mercury_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineQueue(t *testing.T) {
+
+	t.Run("sends straight away while connected", func(t *testing.T) {
+		_, client := makeQueuedClient(t, mercury.OfflineQueueOptions{})
+		client.On("queued-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		_, err := client.Emit("queued-event::v1")
+		require.NoError(t, err)
+	})
+
+	t.Run("holds emits while disconnected and flushes them in order after re-authenticating", func(t *testing.T) {
+		fake, client := makeQueuedClient(t, mercury.OfflineQueueOptions{})
+
+		fakeAuthenticate(fake)
+		_, err := client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.NoError(t, err)
+
+		client.On("queued-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		fake.SimulateDisconnect()
+		fake.ClearEmittedEvents()
+
+		first := emitInBackground(client, "queued-event::v1", "first")
+		second := emitInBackground(client, "queued-event::v1", "second")
+		require.Empty(t, fake.EmittedEvents(), "Nothing should be sent while disconnected")
+
+		restored := waitForRestore(client)
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+		require.NoError(t, receiveEmitResult(t, first))
+		require.NoError(t, receiveEmitResult(t, second))
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 4)
+		require.Equal(t, "authenticate::v2020_12_25", emits[0].Event)
+		require.Equal(t, "register-listeners::v2020_12_25", emits[1].Event)
+		require.Equal(t, "first", emits[2].TargetAndPayload.Payload["name"])
+		require.Equal(t, "second", emits[3].TargetAndPayload.Payload["name"])
+	})
+
+	t.Run("fails queued emits whose context expires", func(t *testing.T) {
+		fake, client := makeQueuedClient(t, mercury.OfflineQueueOptions{})
+		client.On("queued-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		fake.SimulateDisconnect()
+		fake.ClearEmittedEvents()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := client.EmitContext(ctx, "queued-event::v1")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		restored := waitForRestore(client)
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1, "Only the listener should be re-registered")
+		require.Equal(t, "register-listeners::v2020_12_25", emits[0].Event)
+	})
+
+	t.Run("drops the newest emit when full", func(t *testing.T) {
+		fake, client := makeQueuedClient(t, mercury.OfflineQueueOptions{Size: 1, Overflow: mercury.OverflowDropNewest})
+		client.On("queued-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		fake.SimulateDisconnect()
+		first := emitInBackground(client, "queued-event::v1", "first")
+
+		_, err := client.Emit("queued-event::v1")
+		require.ErrorIs(t, err, mercury.ErrQueueFull)
+
+		restored := waitForRestore(client)
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+		require.NoError(t, receiveEmitResult(t, first))
+	})
+
+	t.Run("drops the oldest emit when full", func(t *testing.T) {
+		fake, client := makeQueuedClient(t, mercury.OfflineQueueOptions{Size: 1, Overflow: mercury.OverflowDropOldest})
+		client.On("queued-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		fake.SimulateDisconnect()
+		first := emitInBackground(client, "queued-event::v1", "first")
+		second := emitInBackground(client, "queued-event::v1", "second")
+
+		require.ErrorIs(t, receiveEmitResult(t, first), mercury.ErrQueueFull)
+
+		restored := waitForRestore(client)
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+		require.NoError(t, receiveEmitResult(t, second))
+	})
+
+	t.Run("blocks until there is room", func(t *testing.T) {
+		fake, client := makeQueuedClient(t, mercury.OfflineQueueOptions{Size: 1, Overflow: mercury.OverflowBlock})
+		client.On("queued-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		fake.SimulateDisconnect()
+		first := emitInBackground(client, "queued-event::v1", "first")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := client.EmitContext(ctx, "queued-event::v1")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		second := emitInBackground(client, "queued-event::v1", "second")
+
+		restored := waitForRestore(client)
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+		require.NoError(t, receiveEmitResult(t, first))
+		require.NoError(t, receiveEmitResult(t, second))
+	})
+}
+
+func makeQueuedClient(t *testing.T, opts mercury.OfflineQueueOptions) (*testkit.FakeSocketClient, mercury.MercuryClient) {
+	testkit.BeforeEachInternal(t)
+	fake, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{OfflineQueue: &opts})
+	require.NoError(t, err)
+	return fake, client
+}
+
+// emitInBackground emits from a goroutine and gives it time to reach the
+// queue, so emits queue up in the order they are started.
+func emitInBackground(client mercury.MercuryClient, event string, name string) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.Emit(event, mercury.TargetAndPayload{
+			Payload: map[string]any{"name": name},
+		})
+		result <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	return result
+}
+
+func receiveEmitResult(t *testing.T, result <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for emit")
+		return nil
+	}
+}
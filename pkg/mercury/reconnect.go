@@ -0,0 +1,96 @@
+package mercury
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	ioClient "github.com/zishang520/socket.io/clients/socket/v3"
+)
+
+// ReconnectPolicy controls how the client retries its first connect and how
+// the socket reconnects after the connection drops. Zero fields fall back to
+// the socket.io defaults.
+type ReconnectPolicy struct {
+	// MaxAttempts caps reconnect attempts after a drop. 0 retries forever.
+	MaxAttempts int
+	// MaxConnectAttempts caps attempts for the first connect. Defaults to 3.
+	MaxConnectAttempts int
+	InitialDelay       time.Duration
+	MaxDelay           time.Duration
+	// Jitter randomizes each delay by RandomizationFactor.
+	Jitter              bool
+	RandomizationFactor float64
+}
+
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		MaxConnectAttempts:  3,
+		InitialDelay:        time.Second,
+		MaxDelay:            5 * time.Second,
+		Jitter:              true,
+		RandomizationFactor: 0.5,
+	}
+}
+
+func (p *ReconnectPolicy) withDefaults() ReconnectPolicy {
+	defaults := DefaultReconnectPolicy()
+	if p == nil {
+		return defaults
+	}
+
+	policy := *p
+	if policy.MaxConnectAttempts <= 0 {
+		policy.MaxConnectAttempts = defaults.MaxConnectAttempts
+	}
+	if policy.InitialDelay <= 0 {
+		policy.InitialDelay = defaults.InitialDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = max(defaults.MaxDelay, policy.InitialDelay)
+	}
+	if policy.RandomizationFactor <= 0 {
+		policy.RandomizationFactor = defaults.RandomizationFactor
+	}
+
+	return policy
+}
+
+func (p ReconnectPolicy) randomizationFactor() float64 {
+	if !p.Jitter {
+		return 0
+	}
+	return p.RandomizationFactor
+}
+
+// delay returns how long to wait before the next attempt, doubling from
+// InitialDelay up to MaxDelay the same way socket.io backs off.
+func (p ReconnectPolicy) delay(attempt int) time.Duration {
+	return backoff(p.InitialDelay, p.MaxDelay, p.randomizationFactor(), attempt)
+}
+
+func backoff(initialDelay time.Duration, maxDelay time.Duration, randomizationFactor float64, attempt int) time.Duration {
+	delay := float64(initialDelay) * math.Pow(2, float64(attempt-1))
+
+	if randomizationFactor > 0 {
+		deviation := delay * randomizationFactor * (rand.Float64()*2 - 1)
+		delay += deviation
+	}
+
+	return time.Duration(min(delay, float64(maxDelay)))
+}
+
+func applyReconnectPolicy(socketOptions *ioClient.Options, policy ReconnectPolicy, shouldRetry bool) {
+	socketOptions.SetReconnection(shouldRetry)
+	if !shouldRetry {
+		return
+	}
+
+	if policy.MaxAttempts > 0 {
+		socketOptions.SetReconnectionAttempts(float64(policy.MaxAttempts))
+	}
+
+	socketOptions.SetReconnectionDelay(float64(policy.InitialDelay.Milliseconds()))
+	socketOptions.SetReconnectionDelayMax(float64(policy.MaxDelay.Milliseconds()))
+	socketOptions.SetRandomizationFactor(policy.randomizationFactor())
+}
@@ -0,0 +1,95 @@
+package mercury_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+	ioClient "github.com/zishang520/socket.io/clients/socket/v3"
+)
+
+func TestReconnectPolicy(t *testing.T) {
+
+	t.Run("maps default policy onto socket options", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, _, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		opts := fake.GetOptions()
+		require.True(t, opts.Reconnection())
+		require.Equal(t, float64(1000), opts.ReconnectionDelay())
+		require.Equal(t, float64(5000), opts.ReconnectionDelayMax())
+		require.Equal(t, 0.5, opts.RandomizationFactor())
+	})
+
+	t.Run("maps custom policy onto socket options", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, _, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{
+			ShouldRetryConnect: true,
+			ReconnectPolicy: &mercury.ReconnectPolicy{
+				MaxAttempts:  7,
+				InitialDelay: 250 * time.Millisecond,
+				MaxDelay:     10 * time.Second,
+			},
+		})
+		require.NoError(t, err)
+
+		opts := fake.GetOptions()
+		require.True(t, opts.Reconnection())
+		require.Equal(t, float64(7), opts.ReconnectionAttempts())
+		require.Equal(t, float64(250), opts.ReconnectionDelay())
+		require.Equal(t, float64(10000), opts.ReconnectionDelayMax())
+		require.Equal(t, float64(0), opts.RandomizationFactor(), "No jitter unless asked for")
+	})
+
+	t.Run("retries the first connect", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		calls := failConnectTimes(2)
+
+		_, err := mercury.NewMercuryClient(mercury.MercuryClientOptions{
+			ShouldRetryConnect: true,
+			ReconnectPolicy:    &mercury.ReconnectPolicy{InitialDelay: time.Millisecond},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, *calls)
+	})
+
+	t.Run("gives up after max connect attempts", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		calls := failConnectTimes(10)
+
+		_, err := mercury.NewMercuryClient(mercury.MercuryClientOptions{
+			ShouldRetryConnect: true,
+			ReconnectPolicy: &mercury.ReconnectPolicy{
+				MaxConnectAttempts: 4,
+				InitialDelay:       time.Millisecond,
+			},
+		})
+		require.Error(t, err)
+		require.Equal(t, 4, *calls)
+	})
+
+	t.Run("fails fast when retry is off", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		calls := failConnectTimes(1)
+
+		_, err := mercury.NewMercuryClient(mercury.MercuryClientOptions{ShouldRetryConnect: false})
+		require.Error(t, err)
+		require.Equal(t, 1, *calls)
+	})
+}
+
+func failConnectTimes(times int) *int {
+	calls := 0
+	mercury.SetConnect(func(host string, opts ioClient.OptionsInterface) (mercury.Socket, error) {
+		calls++
+		if calls <= times {
+			return nil, errors.New("connection refused")
+		}
+		return testkit.FakeSocketConnect(host, opts)
+	})
+	return &calls
+}
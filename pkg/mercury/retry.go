@@ -0,0 +1,174 @@
+package mercury
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// EmitRetryPolicy retries emits that fail in ways worth trying again. Mercury
+// has no notion of idempotency, so a client-wide policy only applies to the
+// events listed in Events. Use WithEmitRetry to retry a single emit.
+type EmitRetryPolicy struct {
+	// Events opts fqens into a client-wide policy. It is ignored by
+	// WithEmitRetry.
+	Events []string
+	// MaxAttempts includes the first attempt. Defaults to 3.
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	// Jitter randomizes each delay by RandomizationFactor.
+	Jitter              bool
+	RandomizationFactor float64
+	// AttemptTimeout bounds each attempt, so a lost ack can be retried before
+	// the emit's own deadline. Defaults to the client's emit timeout.
+	AttemptTimeout time.Duration
+	// RetryableCodes lists responder error codes worth retrying.
+	RetryableCodes []string
+	// Retryable decides which emit errors are retried. Defaults to
+	// IsRetryableEmitError.
+	Retryable func(err error) bool
+	// WaitForReconnect holds each retry until the client is connected again.
+	WaitForReconnect bool
+}
+
+type emitRetryKey struct{}
+
+// WithEmitRetry retries emits made with the returned context, whatever their
+// event.
+func WithEmitRetry(ctx context.Context, policy EmitRetryPolicy) context.Context {
+	return context.WithValue(ctx, emitRetryKey{}, policy)
+}
+
+// IsRetryableEmitError reports whether err looks like a transport failure or
+// a lost ack. Validation errors, responder errors and cancellations are not
+// retried.
+func IsRetryableEmitError(err error) bool {
+	var validationErr *ValidationError
+	var spruceErr *SpruceError
+	var aggregateErr *AggregateError
+
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, context.Canceled):
+		return false
+	case errors.As(err, &validationErr), errors.As(err, &spruceErr), errors.As(err, &aggregateErr):
+		return false
+	}
+
+	return true
+}
+
+func (p EmitRetryPolicy) withDefaults(emitTimeout time.Duration) EmitRetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = 250 * time.Millisecond
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = max(5*time.Second, p.InitialDelay)
+	}
+	if p.Jitter && p.RandomizationFactor <= 0 {
+		p.RandomizationFactor = 0.5
+	}
+	if p.AttemptTimeout <= 0 {
+		p.AttemptTimeout = emitTimeout
+	}
+	if p.Retryable == nil {
+		p.Retryable = IsRetryableEmitError
+	}
+	return p
+}
+
+func (p EmitRetryPolicy) delay(attempt int) time.Duration {
+	factor := 0.0
+	if p.Jitter {
+		factor = p.RandomizationFactor
+	}
+	return backoff(p.InitialDelay, p.MaxDelay, factor, attempt)
+}
+
+// retryReason returns why the attempt should be retried, or nil when it
+// should not.
+func (p EmitRetryPolicy) retryReason(ctx context.Context, result *AggregateResult, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if err != nil {
+		if p.Retryable(err) {
+			return err
+		}
+		return nil
+	}
+
+	for _, responderErr := range result.Errors() {
+		if slices.Contains(p.RetryableCodes, responderErr.Code) {
+			return result.Err()
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) emitRetryPolicy(ctx context.Context, event string) (EmitRetryPolicy, bool) {
+	if policy, ok := ctx.Value(emitRetryKey{}).(EmitRetryPolicy); ok {
+		return policy.withDefaults(c.emitTimeout), true
+	}
+
+	if c.emitRetry != nil && slices.Contains(c.emitRetry.Events, event) {
+		return c.emitRetry.withDefaults(c.emitTimeout), true
+	}
+
+	return EmitRetryPolicy{}, false
+}
+
+func (c *Client) emitWithRetry(ctx context.Context, event string, targetAndPayload TargetAndPayload) (*AggregateResult, error) {
+	policy, ok := c.emitRetryPolicy(ctx, event)
+	if !ok {
+		return c.emitAggregate(ctx, event, targetAndPayload)
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if policy.AttemptTimeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, policy.AttemptTimeout)
+		}
+
+		result, err := c.emitAggregate(attemptCtx, event, targetAndPayload)
+		cancel()
+
+		reason := policy.retryReason(ctx, result, err)
+		if reason == nil || attempt >= policy.MaxAttempts {
+			return result, err
+		}
+
+		delay := policy.delay(attempt)
+		c.log().Info("Retrying emit", "event", event, "attempt", attempt+1, "delay", delay, "error", reason)
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+
+		if policy.WaitForReconnect {
+			if err := c.waitForConnection(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
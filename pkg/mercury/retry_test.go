@@ -0,0 +1,257 @@
+package mercury_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitRetry(t *testing.T) {
+
+	t.Run("does not retry events that did not opt in", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{
+			EmitRetry: fastRetry("other-event::v1"),
+		})
+		require.NoError(t, err)
+
+		client.On("flaky-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		fake.MakeEventFailTimes("flaky-event::v1", 1, errors.New("transport closed"))
+
+		_, err = client.Emit("flaky-event::v1")
+		require.EqualError(t, err, "transport closed")
+	})
+
+	t.Run("retries transport errors for events that opted in", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{
+			EmitRetry: fastRetry("flaky-event::v1"),
+		})
+		require.NoError(t, err)
+
+		client.On("flaky-event::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"ok": true}
+		})
+		fake.MakeEventFailTimes("flaky-event::v1", 2, errors.New("transport closed"))
+		fake.ClearEmittedEvents()
+
+		responses, err := client.Emit("flaky-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"ok": true}}, responses)
+		require.Len(t, fake.EmittedEvents(), 3)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{
+			EmitRetry: fastRetry("flaky-event::v1"),
+		})
+		require.NoError(t, err)
+
+		fake.MakeEventFailTimes("flaky-event::v1", 5, errors.New("transport closed"))
+		fake.ClearEmittedEvents()
+
+		_, err = client.Emit("flaky-event::v1")
+		require.EqualError(t, err, "transport closed")
+		require.Len(t, fake.EmittedEvents(), 3)
+	})
+
+	t.Run("retries lost acks with a timeout per attempt", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("flaky-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		fake.MakeEventFailTimes("flaky-event::v1", 1, nil)
+
+		policy := *fastRetry()
+		policy.AttemptTimeout = 20 * time.Millisecond
+
+		_, err = client.EmitContext(mercury.WithEmitRetry(context.Background(), policy), "flaky-event::v1")
+		require.NoError(t, err)
+	})
+
+	t.Run("retries responder errors with retryable codes", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		calls := 0
+		client.On("flaky-event::v1", func(mercury.TargetAndPayload) any {
+			calls++
+			if calls == 1 {
+				return errors.New("warming up")
+			}
+			return nil
+		})
+
+		policy := *fastRetry()
+		policy.RetryableCodes = []string{mercury.ErrorCodeListenerError}
+
+		_, err = client.EmitContext(mercury.WithEmitRetry(context.Background(), policy), "flaky-event::v1")
+		require.NoError(t, err)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("does not retry responder errors by default", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		calls := 0
+		client.On("flaky-event::v1", func(mercury.TargetAndPayload) any {
+			calls++
+			return errors.New("nope")
+		})
+
+		_, err = client.EmitContext(mercury.WithEmitRetry(context.Background(), *fastRetry()), "flaky-event::v1")
+		require.ErrorIs(t, err, mercury.ErrListenerError)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("uses the retryable predicate", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		permanent := errors.New("permanent")
+		fake.MakeEventFailTimes("flaky-event::v1", 5, permanent)
+		fake.ClearEmittedEvents()
+
+		policy := *fastRetry()
+		policy.Retryable = func(err error) bool { return !errors.Is(err, permanent) }
+
+		_, err = client.EmitContext(mercury.WithEmitRetry(context.Background(), policy), "flaky-event::v1")
+		require.ErrorIs(t, err, permanent)
+		require.Len(t, fake.EmittedEvents(), 1)
+	})
+
+	t.Run("waits for reconnect before retrying", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("flaky-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		fake.MakeEventFailTimes("flaky-event::v1", 1, errors.New("transport closed"))
+		fake.SimulateDisconnect()
+
+		policy := *fastRetry()
+		policy.WaitForReconnect = true
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := client.EmitContext(mercury.WithEmitRetry(context.Background(), policy), "flaky-event::v1")
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			t.Fatalf("Emit should wait for reconnect, finished with %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		restored := waitForRestore(client)
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("Emit should retry after reconnect")
+		}
+	})
+
+	t.Run("waits for the session to be restored before retrying", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var gate chan struct{}
+		var gateMu sync.Mutex
+		fake.On("authenticate::v2020_12_25", func(args ...any) {
+			gateMu.Lock()
+			wait := gate
+			gateMu.Unlock()
+			if wait != nil {
+				<-wait
+			}
+			testkit.PluckCallback(args)([]any{mercury.ResponsePayload{
+				"auth": map[string]any{"person": map[string]any{"id": "person-1", "casualName": "friend"}},
+			}}, nil)
+		})
+
+		_, err = client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.NoError(t, err)
+
+		client.On("flaky-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		fake.MakeEventFailTimes("flaky-event::v1", 1, errors.New("transport closed"))
+		fake.SimulateDisconnect()
+
+		release := make(chan struct{})
+		gateMu.Lock()
+		gate = release
+		gateMu.Unlock()
+
+		policy := *fastRetry()
+		policy.WaitForReconnect = true
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := client.EmitContext(mercury.WithEmitRetry(context.Background(), policy), "flaky-event::v1")
+			done <- err
+		}()
+
+		restored := waitForRestore(client)
+		fake.SimulateReconnect()
+		require.Equal(t, mercury.ConnectionStateReconnected, client.State())
+
+		select {
+		case err := <-done:
+			t.Fatalf("Emit should wait for re-authentication, finished with %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		require.NoError(t, receiveRestore(t, restored))
+
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("Emit should retry once the session is restored")
+		}
+	})
+
+	t.Run("stops retrying when the context is done", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fake.MakeEventFailTimes("flaky-event::v1", 5, errors.New("transport closed"))
+		fake.SimulateDisconnect()
+
+		policy := *fastRetry()
+		policy.WaitForReconnect = true
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+
+		_, err = client.EmitContext(mercury.WithEmitRetry(ctx, policy), "flaky-event::v1")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func fastRetry(events ...string) *mercury.EmitRetryPolicy {
+	return &mercury.EmitRetryPolicy{
+		Events:       events,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+	}
+}
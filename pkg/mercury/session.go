@@ -0,0 +1,172 @@
+package mercury
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type sessionRestoreKey struct{}
+
+// OnSessionRestored registers a callback that runs every time the client
+// reconnects and has replayed its last authentication and listener
+// registrations. err is set when any part of the replay failed.
+func (c *Client) OnSessionRestored(cb func(err error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onSessionRestored = append(c.onSessionRestored, cb)
+}
+
+func (c *Client) restoreSession() {
+	c.mu.Lock()
+	auth := c.lastAuth
+	events := slices.Clone(c.listenerEvents)
+	c.mu.Unlock()
+
+	auth = c.sessionAuth(auth)
+
+	err := c.replaySession(auth, events)
+	if err != nil {
+		c.log().Error("Failed to restore session after reconnect", "error", err)
+	} else {
+		c.log().Info("Restored session after reconnect", "listeners", len(events), "authenticated", auth != nil)
+	}
+
+	if c.queue != nil {
+		c.queue.flush()
+	}
+
+	c.setRestoring(false)
+
+	c.mu.Lock()
+	callbacks := slices.Clone(c.onSessionRestored)
+	c.mu.Unlock()
+
+	for _, cb := range callbacks {
+		cb(err)
+	}
+}
+
+func (c *Client) replaySession(auth *AuthenticatePayload, events []string) error {
+	ctx := context.WithValue(context.Background(), sessionRestoreKey{}, true)
+
+	if auth != nil {
+		if _, err := c.authenticate(ctx, *auth); err != nil {
+			return fmt.Errorf("failed to re-authenticate after reconnect: %w", err)
+		}
+	}
+
+	if len(events) > 0 {
+		if err := c.registerListeners(ctx, events...); err != nil {
+			return fmt.Errorf("failed to re-register listeners after reconnect: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) rememberAuth(opts AuthenticatePayload) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastAuth = &opts
+}
+
+// sessionAuth picks what to authenticate with when a session is restored.
+// Skills keep their api key; people use whatever token is stored, which may
+// be newer than the one they last authenticated with.
+func (c *Client) sessionAuth(auth *AuthenticatePayload) *AuthenticatePayload {
+	if auth != nil && auth.Token == "" {
+		return auth
+	}
+
+	token := c.storedToken()
+	if token == "" {
+		return auth
+	}
+
+	return &AuthenticatePayload{Token: token}
+}
+
+// resumeStoredSession authenticates with the stored token, if any, when the
+// client first connects.
+func (c *Client) resumeStoredSession() {
+	auth := c.sessionAuth(nil)
+	if auth == nil {
+		return
+	}
+
+	if _, err := c.authenticate(context.Background(), *auth); err != nil {
+		c.log().Warn("Failed to resume session with stored token", "error", err)
+		return
+	}
+
+	c.log().Info("Resumed session with stored token")
+}
+
+func (c *Client) storedToken() string {
+	c.mu.Lock()
+	tokens := c.tokens
+	c.mu.Unlock()
+
+	if tokens == nil {
+		return ""
+	}
+
+	token, err := tokens.Token()
+	if err != nil {
+		c.log().Error("Failed to read stored token", "error", err)
+		return ""
+	}
+	return token
+}
+
+func (c *Client) storeToken(token string) {
+	c.mu.Lock()
+	tokens := c.tokens
+	c.mu.Unlock()
+
+	if tokens == nil {
+		return
+	}
+
+	if err := tokens.SetToken(token); err != nil {
+		c.log().Error("Failed to store token", "error", err)
+	}
+}
+
+// forgetToken drops a token Mercury rejected, both from the store and from
+// the auth replayed on reconnect.
+func (c *Client) forgetToken(token string) {
+	c.mu.Lock()
+	tokens := c.tokens
+	if c.lastAuth != nil && c.lastAuth.Token == token {
+		c.lastAuth = nil
+		c.currentAuth = nil
+	}
+	c.mu.Unlock()
+
+	if tokens == nil || c.storedToken() != token {
+		return
+	}
+
+	c.log().Warn("Clearing rejected token")
+	if err := tokens.ClearToken(); err != nil {
+		c.log().Error("Failed to clear token", "error", err)
+	}
+}
+
+// isAuthFailure reports whether Mercury answered an authenticate emit with an
+// error, rather than the emit never making it there.
+func isAuthFailure(err error) bool {
+	var spruceErr *SpruceError
+	var aggregateErr *AggregateError
+	return errors.As(err, &spruceErr) || errors.As(err, &aggregateErr)
+}
+
+// isSessionRestore reports whether ctx belongs to a session replay, which has
+// to reach Mercury before anything held in the offline queue.
+func isSessionRestore(ctx context.Context) bool {
+	restoring, _ := ctx.Value(sessionRestoreKey{}).(bool)
+	return restoring
+}
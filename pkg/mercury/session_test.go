@@ -0,0 +1,128 @@
+package mercury_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRestore(t *testing.T) {
+
+	t.Run("replays authentication then listeners after reconnect", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fakeAuthenticate(fake)
+
+		_, err = client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.NoError(t, err)
+
+		client.On("first-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		client.On("second-event::v1", func(mercury.TargetAndPayload) any { return nil })
+
+		restored := waitForRestore(client)
+		fake.ClearEmittedEvents()
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 2)
+		require.Equal(t, "authenticate::v2020_12_25", emits[0].Event)
+		require.Equal(t, "token-1", emits[0].TargetAndPayload.Payload["token"])
+		require.Equal(t, "register-listeners::v2020_12_25", emits[1].Event)
+		require.Equal(t, []string{"first-event::v1", "second-event::v1"}, registeredEventNames(emits[1]))
+	})
+
+	t.Run("does not replay listeners that were turned off", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("first-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		client.On("second-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		client.Off("first-event::v1")
+
+		restored := waitForRestore(client)
+		fake.ClearEmittedEvents()
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1, "Anonymous clients should only re-register listeners")
+		require.Equal(t, []string{"second-event::v1"}, registeredEventNames(emits[0]))
+	})
+
+	t.Run("reports failed re-authentication", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fakeAuthenticate(fake)
+		_, err = client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.NoError(t, err)
+
+		fake.MakeEventReturnError("authenticate::v2020_12_25", errors.New("token expired"))
+
+		restored := waitForRestore(client)
+		fake.SimulateReconnect()
+
+		restoreErr := receiveRestore(t, restored)
+		require.Error(t, restoreErr)
+		require.Contains(t, restoreErr.Error(), "token expired")
+	})
+
+	t.Run("reports re-authentication that gets no responses", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		fakeAuthenticate(fake)
+		_, err = client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.NoError(t, err)
+
+		fake.MakeEventReturnResponses("authenticate::v2020_12_25", nil)
+
+		restored := waitForRestore(client)
+		fake.SimulateReconnect()
+		require.ErrorContains(t, receiveRestore(t, restored), "authenticate returned no responses")
+	})
+}
+
+func fakeAuthenticate(fake *testkit.FakeSocketClient) {
+	fake.MakeEventReturnResponses("authenticate::v2020_12_25", []mercury.ResponsePayload{
+		{"auth": map[string]any{"person": map[string]any{"id": "person-1", "casualName": "friend"}}},
+	})
+}
+
+func waitForRestore(client mercury.MercuryClient) chan error {
+	restored := make(chan error, 1)
+	client.OnSessionRestored(func(err error) {
+		restored <- err
+	})
+	return restored
+}
+
+func registeredEventNames(emit testkit.FakeEmit) []string {
+	events, _ := emit.TargetAndPayload.Payload["events"].([]map[string]string)
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = event["eventName"]
+	}
+	return names
+}
+
+func receiveRestore(t *testing.T, restored chan error) error {
+	t.Helper()
+	select {
+	case err := <-restored:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("Session was never restored")
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+package mercury
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// TokenStore keeps a person's session token between connections. Token
+// returns an empty string when nothing is stored.
+type TokenStore interface {
+	Token() (string, error)
+	SetToken(token string) error
+	ClearToken() error
+}
+
+// MemoryTokenStore keeps the token for the life of the process. The zero
+// value is ready to use.
+type MemoryTokenStore struct {
+	mu    sync.Mutex
+	token string
+}
+
+func (s *MemoryTokenStore) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token, nil
+}
+
+func (s *MemoryTokenStore) SetToken(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+	return nil
+}
+
+func (s *MemoryTokenStore) ClearToken() error {
+	return s.SetToken("")
+}
+
+// FileTokenStore keeps the token in a file only the current user can read,
+// so a session can be resumed after a restart.
+type FileTokenStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+func (s *FileTokenStore) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SetToken writes the token to a temporary file and renames it into place, so
+// a crash never leaves half a token behind.
+func (s *FileTokenStore) SetToken(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(dir, filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(token); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), s.path)
+}
+
+func (s *FileTokenStore) ClearToken() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
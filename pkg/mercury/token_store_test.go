@@ -0,0 +1,158 @@
+package mercury_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenStores(t *testing.T) {
+
+	t.Run("memory store keeps and clears a token", func(t *testing.T) {
+		store := &mercury.MemoryTokenStore{}
+		assertStoreRoundTrips(t, store)
+	})
+
+	t.Run("file store keeps and clears a token", func(t *testing.T) {
+		store := mercury.NewFileTokenStore(filepath.Join(t.TempDir(), "session", "token"))
+		assertStoreRoundTrips(t, store)
+	})
+
+	t.Run("file store survives a restart", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, mercury.NewFileTokenStore(path).SetToken("token-1"))
+
+		token, err := mercury.NewFileTokenStore(path).Token()
+		require.NoError(t, err)
+		require.Equal(t, "token-1", token)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+}
+
+func TestTokenSessions(t *testing.T) {
+
+	t.Run("stores the token a person authenticates with", func(t *testing.T) {
+		store := &mercury.MemoryTokenStore{}
+		fake, client := makeTokenClient(t, store)
+
+		fakeAuthenticate(fake)
+		_, err := client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.NoError(t, err)
+
+		requireStoredToken(t, store, "token-1")
+	})
+
+	t.Run("resumes the stored session when connecting", func(t *testing.T) {
+		store := &mercury.MemoryTokenStore{}
+		require.NoError(t, store.SetToken("token-1"))
+
+		fake, _ := makeTokenClient(t, store)
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Equal(t, "authenticate::v2020_12_25", emits[0].Event)
+		require.Equal(t, "token-1", emits[0].TargetAndPayload.Payload["token"])
+	})
+
+	t.Run("authenticates with the stored token after reconnect", func(t *testing.T) {
+		store := &mercury.MemoryTokenStore{}
+		fake, client := makeTokenClient(t, store)
+
+		fakeAuthenticate(fake)
+		_, err := client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.NoError(t, err)
+		require.NoError(t, store.SetToken("token-2"))
+
+		restored := waitForRestore(client)
+		fake.ClearEmittedEvents()
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Equal(t, "token-2", emits[0].TargetAndPayload.Payload["token"])
+	})
+
+	t.Run("clears the token when Mercury rejects it", func(t *testing.T) {
+		store := &mercury.MemoryTokenStore{}
+		fake, client := makeTokenClient(t, store)
+
+		fakeAuthenticate(fake)
+		_, err := client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.NoError(t, err)
+
+		fake.MakeEventReturnResponses("authenticate::v2020_12_25", []mercury.ResponsePayload{
+			{"errors": []any{map[string]any{"code": "INVALID_AUTH_TOKEN"}}},
+		})
+
+		restored := waitForRestore(client)
+		fake.SimulateReconnect()
+		require.Error(t, receiveRestore(t, restored))
+		requireStoredToken(t, store, "")
+
+		restored = waitForRestore(client)
+		fake.ClearEmittedEvents()
+		fake.SimulateReconnect()
+		require.NoError(t, receiveRestore(t, restored))
+		require.Empty(t, fake.EmittedEvents(), "The rejected token should not be replayed")
+	})
+
+	t.Run("keeps the token when authenticating never reached Mercury", func(t *testing.T) {
+		store := &mercury.MemoryTokenStore{}
+		fake, client := makeTokenClient(t, store)
+
+		fakeAuthenticate(fake)
+		_, err := client.Authenticate(mercury.AuthenticatePayload{Token: "token-1"})
+		require.NoError(t, err)
+
+		fake.MakeEventReturnError("authenticate::v2020_12_25", errors.New("transport closed"))
+
+		restored := waitForRestore(client)
+		fake.SimulateReconnect()
+		require.Error(t, receiveRestore(t, restored))
+		requireStoredToken(t, store, "token-1")
+	})
+}
+
+func assertStoreRoundTrips(t *testing.T, store mercury.TokenStore) {
+	t.Helper()
+	requireStoredToken(t, store, "")
+
+	require.NoError(t, store.SetToken("token-1"))
+	requireStoredToken(t, store, "token-1")
+
+	require.NoError(t, store.SetToken("token-2"))
+	requireStoredToken(t, store, "token-2")
+
+	require.NoError(t, store.ClearToken())
+	requireStoredToken(t, store, "")
+	require.NoError(t, store.ClearToken(), "Clearing twice should be fine")
+}
+
+func requireStoredToken(t *testing.T, store mercury.TokenStore, expected string) {
+	t.Helper()
+	token, err := store.Token()
+	require.NoError(t, err)
+	require.Equal(t, expected, token)
+}
+
+// makeTokenClient fakes authenticate before connecting, so a stored session
+// can be resumed during Connect.
+func makeTokenClient(t *testing.T, store mercury.TokenStore) (*testkit.FakeSocketClient, mercury.MercuryClient) {
+	testkit.BeforeEachInternal(t)
+	_, err := testkit.FakeSocketConnect("", nil)
+	require.NoError(t, err)
+	fakeAuthenticate(testkit.LastFakeSocket())
+
+	fake, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{TokenStore: store})
+	require.NoError(t, err)
+	return fake, client
+}
@@ -0,0 +1,118 @@
+package mercury
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type (
+	// TypedTargetAndPayload is TargetAndPayload decoded into structs.
+	TypedTargetAndPayload[TTarget, TPayload any] struct {
+		Source  map[string]any
+		Target  TTarget
+		Payload TPayload
+	}
+
+	TypedListener[TTarget, TPayload, TResponse any] = func(targetAndPayload TypedTargetAndPayload[TTarget, TPayload]) (TResponse, error)
+
+	// DecodeError reports which field could not be decoded, e.g.
+	// "payload.messages".
+	DecodeError struct {
+		Path string
+		Err  error
+	}
+)
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode '%s': %v", e.Path, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// EmitTyped emits target and payload as structs and decodes every response
+// into TResponse.
+func EmitTyped[TTarget, TPayload, TResponse any](client MercuryClient, fqen string, target TTarget, payload TPayload) ([]TResponse, error) {
+	return EmitTypedContext[TTarget, TPayload, TResponse](context.Background(), client, fqen, target, payload)
+}
+
+func EmitTypedContext[TTarget, TPayload, TResponse any](ctx context.Context, client MercuryClient, fqen string, target TTarget, payload TPayload) ([]TResponse, error) {
+	targetAndPayload := TargetAndPayload{}
+
+	if err := encode(target, &targetAndPayload.Target); err != nil {
+		return nil, fmt.Errorf("failed to encode '%s' target: %w", fqen, err)
+	}
+
+	if err := encode(payload, &targetAndPayload.Payload); err != nil {
+		return nil, fmt.Errorf("failed to encode '%s' payload: %w", fqen, err)
+	}
+
+	responses, err := client.EmitContext(ctx, fqen, targetAndPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	typed := make([]TResponse, len(responses))
+	for i, response := range responses {
+		if err := decode(response, &typed[i], "response"); err != nil {
+			return nil, err
+		}
+	}
+
+	return typed, nil
+}
+
+// OnTyped listens to fqen and decodes target and payload into structs before
+// calling listener. Decode failures are sent back as listener errors.
+func OnTyped[TTarget, TPayload, TResponse any](client MercuryClient, fqen string, listener TypedListener[TTarget, TPayload, TResponse], opts ...ListenerOptions) (*Subscription, error) {
+	return client.On(fqen, func(targetAndPayload TargetAndPayload) any {
+		typed := TypedTargetAndPayload[TTarget, TPayload]{
+			Source: targetAndPayload.Source,
+		}
+
+		if err := decode(targetAndPayload.Target, &typed.Target, "target"); err != nil {
+			return err
+		}
+
+		if err := decode(targetAndPayload.Payload, &typed.Payload, "payload"); err != nil {
+			return err
+		}
+
+		response, err := listener(typed)
+		if err != nil {
+			return err
+		}
+
+		var mapped ResponsePayload
+		if err := encode(response, &mapped); err != nil {
+			return fmt.Errorf("failed to encode '%s' response: %w", fqen, err)
+		}
+
+		return mapped
+	}, opts...)
+}
+
+func encode(value any, out *map[string]any) error {
+	return mapToStruct(value, out)
+}
+
+func decode(value any, out any, path string) error {
+	if value == nil {
+		return nil
+	}
+
+	err := mapToStruct(value, out)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		path = path + "." + typeErr.Field
+	}
+
+	return &DecodeError{Path: path, Err: err}
+}
@@ -0,0 +1,92 @@
+package mercury_test
+
+import (
+	"errors"
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+type vipTarget struct {
+	OrganizationId string `json:"organizationId"`
+}
+
+type vipPayload struct {
+	Message string `json:"message"`
+}
+
+type vipResponse struct {
+	Messages []string `json:"messages"`
+}
+
+func TestTyped(t *testing.T) {
+
+	t.Run("round trips structs through emit and on", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		var received mercury.TypedTargetAndPayload[vipTarget, vipPayload]
+		mercury.OnTyped(client, "will-send-vip::v1", func(targetAndPayload mercury.TypedTargetAndPayload[vipTarget, vipPayload]) (vipResponse, error) {
+			received = targetAndPayload
+			return vipResponse{Messages: []string{"hey " + targetAndPayload.Payload.Message}}, nil
+		})
+
+		responses, err := mercury.EmitTyped[vipTarget, vipPayload, vipResponse](client, "will-send-vip::v1", vipTarget{OrganizationId: "org-1"}, vipPayload{Message: "vip"})
+		require.NoError(t, err)
+		require.Equal(t, []vipResponse{{Messages: []string{"hey vip"}}}, responses)
+		require.Equal(t, "org-1", received.Target.OrganizationId)
+		require.Equal(t, "vip", received.Payload.Message)
+	})
+
+	t.Run("names the field path when a response fails to decode", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		client.On("will-send-vip::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"messages": 42}
+		})
+
+		_, err = mercury.EmitTyped[vipTarget, vipPayload, vipResponse](client, "will-send-vip::v1", vipTarget{}, vipPayload{})
+
+		var decodeErr *mercury.DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		require.Equal(t, "response.messages", decodeErr.Path)
+	})
+
+	t.Run("sends payload decode failures back as listener errors", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		wasHit := false
+		mercury.OnTyped(client, "will-send-vip::v1", func(mercury.TypedTargetAndPayload[vipTarget, vipPayload]) (vipResponse, error) {
+			wasHit = true
+			return vipResponse{}, nil
+		})
+
+		_, err = client.Emit("will-send-vip::v1", mercury.TargetAndPayload{
+			Payload: map[string]any{"message": []any{"not", "a", "string"}},
+		})
+
+		require.False(t, wasHit, "Listener should not run with a bad payload")
+		require.ErrorIs(t, err, mercury.ErrListenerError)
+		require.ErrorContains(t, err, "payload.message")
+	})
+
+	t.Run("passes listener errors back", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient()
+		require.NoError(t, err)
+
+		mercury.OnTyped(client, "will-send-vip::v1", func(mercury.TypedTargetAndPayload[vipTarget, vipPayload]) (vipResponse, error) {
+			return vipResponse{}, errors.New("not today")
+		})
+
+		_, err = mercury.EmitTyped[vipTarget, vipPayload, vipResponse](client, "will-send-vip::v1", vipTarget{}, vipPayload{})
+		require.ErrorContains(t, err, "not today")
+	})
+}
@@ -0,0 +1,223 @@
+package mercury
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	FieldErrorMissingRequired = "missing_required"
+	FieldErrorInvalidValue    = "invalid_value"
+	FieldErrorUnexpectedValue = "unexpected_value"
+)
+
+type (
+	// Validator checks emits and responses against event signatures before
+	// they cost a round trip.
+	Validator struct {
+		mu         sync.RWMutex
+		signatures map[string]EventSignature
+	}
+
+	// ValidationError lists every field that failed validation for one event.
+	ValidationError struct {
+		Fqen   string
+		Fields []FieldError
+	}
+
+	FieldError struct {
+		// Path is the dotted path to the field, e.g. "payload.message".
+		Path    string
+		Code    string
+		Message string
+	}
+)
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		fields[i] = fmt.Sprintf("%s (%s)", field.Path, field.Code)
+	}
+	return fmt.Sprintf("'%s' failed validation: %s", e.Fqen, strings.Join(fields, ", "))
+}
+
+func NewValidator(contracts ...*EventContract) *Validator {
+	v := &Validator{signatures: map[string]EventSignature{}}
+	for _, contract := range contracts {
+		v.AddContract(contract)
+	}
+	return v
+}
+
+// AddContract adds or replaces the signatures in contract.
+func (v *Validator) AddContract(contract *EventContract) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for fqen, signature := range contract.EventSignatures {
+		v.signatures[fqen] = signature
+	}
+}
+
+func (v *Validator) signature(fqen string) (EventSignature, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	signature, ok := v.signatures[fqen]
+	return signature, ok
+}
+
+// ValidateEmit checks target and payload against the event's
+// emitPayloadSchema. Events without a known signature always pass.
+func (v *Validator) ValidateEmit(fqen string, targetAndPayload TargetAndPayload) error {
+	signature, ok := v.signature(fqen)
+	if !ok || signature.EmitPayloadSchema == nil {
+		return nil
+	}
+
+	values := map[string]any{}
+	if err := mapToStruct(targetAndPayload, &values); err != nil {
+		return err
+	}
+
+	delete(values, "source")
+
+	var fields []FieldError
+	validateSchema(signature.EmitPayloadSchema, values, "", &fields)
+
+	return validationResult(fqen, fields)
+}
+
+// ValidateResponse checks a responder's payload against the event's
+// responsePayloadSchema. Events without a known signature always pass.
+func (v *Validator) ValidateResponse(fqen string, payload ResponsePayload) error {
+	signature, ok := v.signature(fqen)
+	if !ok || signature.ResponsePayloadSchema == nil {
+		return nil
+	}
+
+	values := map[string]any{}
+	if err := mapToStruct(payload, &values); err != nil {
+		return err
+	}
+
+	var fields []FieldError
+	validateSchema(signature.ResponsePayloadSchema, values, "", &fields)
+
+	return validationResult(fqen, fields)
+}
+
+func validationResult(fqen string, fields []FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fqen: fqen, Fields: fields}
+}
+
+func validateSchema(schema *Schema, values map[string]any, path string, fields *[]FieldError) {
+	keys := make([]string, 0, len(schema.Fields))
+	for key := range schema.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		definition := schema.Fields[key]
+		value, ok := values[key]
+		fieldPath := joinPath(path, key)
+
+		if !ok || value == nil {
+			if definition.IsRequired {
+				*fields = append(*fields, FieldError{Path: fieldPath, Code: FieldErrorMissingRequired, Message: "is required"})
+			}
+			continue
+		}
+
+		validateField(definition, value, fieldPath, fields)
+	}
+
+	var unexpected []string
+	for key := range values {
+		if _, ok := schema.Fields[key]; !ok {
+			unexpected = append(unexpected, key)
+		}
+	}
+	sort.Strings(unexpected)
+
+	for _, key := range unexpected {
+		*fields = append(*fields, FieldError{Path: joinPath(path, key), Code: FieldErrorUnexpectedValue, Message: "is not in the schema"})
+	}
+}
+
+func validateField(definition FieldDefinition, value any, path string, fields *[]FieldError) {
+	if definition.IsArray {
+		items, ok := value.([]any)
+		if !ok {
+			*fields = append(*fields, FieldError{Path: path, Code: FieldErrorInvalidValue, Message: "must be an array"})
+			return
+		}
+
+		if definition.IsRequired && len(items) == 0 {
+			*fields = append(*fields, FieldError{Path: path, Code: FieldErrorMissingRequired, Message: "needs at least one value"})
+		}
+
+		for i, item := range items {
+			validateValue(definition, item, fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+		return
+	}
+
+	validateValue(definition, value, path, fields)
+}
+
+func validateValue(definition FieldDefinition, value any, path string, fields *[]FieldError) {
+	invalid := func(message string) {
+		*fields = append(*fields, FieldError{Path: path, Code: FieldErrorInvalidValue, Message: message})
+	}
+
+	switch definition.Type {
+	case "id", "text", "phone", "email", "password", "schemaId":
+		if _, ok := value.(string); !ok {
+			invalid("must be a string")
+		}
+	case "select":
+		choice, ok := value.(string)
+		if !ok {
+			invalid("must be a string")
+			return
+		}
+		if definition.Options != nil && len(definition.Options.Choices) > 0 {
+			isChoice := slices.ContainsFunc(definition.Options.Choices, func(c SelectChoice) bool {
+				return c.Value == choice
+			})
+			if !isChoice {
+				invalid("is not one of the choices")
+			}
+		}
+	case "number", "date", "dateTime":
+		if _, ok := value.(float64); !ok {
+			invalid("must be a number")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			invalid("must be a boolean")
+		}
+	case "schema":
+		values, ok := value.(map[string]any)
+		if !ok {
+			invalid("must be an object")
+			return
+		}
+		if definition.Options != nil && definition.Options.Schema != nil {
+			validateSchema(definition.Options.Schema, values, path, fields)
+		}
+	}
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
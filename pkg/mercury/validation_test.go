@@ -0,0 +1,98 @@
+package mercury_test
+
+import (
+	"testing"
+
+	mercury "github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidation(t *testing.T) {
+
+	t.Run("passes valid emits", func(t *testing.T) {
+		validator := makeVipValidator(t)
+		err := validator.ValidateEmit("will-send-vip::v1", mercury.TargetAndPayload{
+			Target:  map[string]any{"organizationId": "org-1"},
+			Payload: map[string]any{"message": "hey"},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("lists every offending field", func(t *testing.T) {
+		validator := makeVipValidator(t)
+		err := validator.ValidateEmit("will-send-vip::v1", mercury.TargetAndPayload{
+			Target:  map[string]any{"organizationId": 42, "locationId": "loc-1"},
+			Payload: nil,
+		})
+
+		var validationErr *mercury.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, "will-send-vip::v1", validationErr.Fqen)
+		require.Equal(t, []mercury.FieldError{
+			{Path: "payload", Code: mercury.FieldErrorMissingRequired, Message: "is required"},
+			{Path: "target.organizationId", Code: mercury.FieldErrorInvalidValue, Message: "must be a string"},
+			{Path: "target.locationId", Code: mercury.FieldErrorUnexpectedValue, Message: "is not in the schema"},
+		}, validationErr.Fields)
+	})
+
+	t.Run("validates responses", func(t *testing.T) {
+		validator := makeVipValidator(t)
+		require.NoError(t, validator.ValidateResponse("will-send-vip::v1", mercury.ResponsePayload{"messages": []string{"hi"}}))
+
+		err := validator.ValidateResponse("will-send-vip::v1", mercury.ResponsePayload{"messages": []any{"hi", 3}})
+		var validationErr *mercury.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, "messages[1]", validationErr.Fields[0].Path)
+
+		err = validator.ValidateResponse("will-send-vip::v1", mercury.ResponsePayload{})
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, mercury.FieldErrorMissingRequired, validationErr.Fields[0].Code)
+	})
+
+	t.Run("skips events it has no signature for", func(t *testing.T) {
+		validator := makeVipValidator(t)
+		require.NoError(t, validator.ValidateEmit("unknown::v1", mercury.TargetAndPayload{Payload: map[string]any{"anything": true}}))
+	})
+
+	t.Run("client rejects invalid emits before sending", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		fake, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{Validator: makeVipValidator(t)})
+		require.NoError(t, err)
+		fake.ClearEmittedEvents()
+
+		_, err = client.Emit("will-send-vip::v1", mercury.TargetAndPayload{
+			Target: map[string]any{"organizationId": "org-1"},
+		})
+
+		var validationErr *mercury.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Empty(t, fake.EmittedEvents(), "Invalid emits should never reach the socket")
+	})
+
+	t.Run("client rejects invalid responses", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		_, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{Validator: makeVipValidator(t)})
+		require.NoError(t, err)
+
+		client.On("will-send-vip::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"message": "wrong field"}
+		})
+
+		_, err = client.Emit("will-send-vip::v1", mercury.TargetAndPayload{
+			Target:  map[string]any{"organizationId": "org-1"},
+			Payload: map[string]any{"message": "hey"},
+		})
+
+		var validationErr *mercury.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, "messages", validationErr.Fields[0].Path)
+	})
+}
+
+func makeVipValidator(t *testing.T) *mercury.Validator {
+	t.Helper()
+	contract, err := mercury.ParseEventContract(testkit.GenerateWillSendVipEventSignature())
+	require.NoError(t, err)
+	return mercury.NewValidator(contract)
+}
@@ -0,0 +1,207 @@
+// Package otelmercury traces Mercury emits and listeners with OpenTelemetry.
+// Trace context travels in the payload's traceContext field, so an emit from
+// one skill and the listener that answers it in another show up as one trace.
+//
+// Mercury validates payloads against the event's contract and only forwards
+// fields the contract declares, so an event carries trace context only if its
+// emitPayloadSchema has an optional traceContext field of type raw. Nothing is
+// propagated until Options.Propagate picks the events that do, and core events
+// never carry it.
+package otelmercury
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/sprucelabsai-community/mercury-client-go/pkg/otelmercury"
+
+// TraceContextField is the payload field that carries trace context.
+const TraceContextField = "traceContext"
+
+const (
+	EventKey     = attribute.Key("mercury.event")
+	ResponsesKey = attribute.Key("mercury.responses")
+	ErrorsKey    = attribute.Key("mercury.errors")
+)
+
+// Options configures tracing. TracerProvider defaults to the global provider
+// and Propagator to W3C trace context.
+type Options struct {
+	TracerProvider trace.TracerProvider
+	Propagator     propagation.TextMapPropagator
+	// Propagate reports whether emits of event carry trace context. Only
+	// return true for events whose contract declares the field. Spans are
+	// still recorded, but no trace context is sent, when it is nil, and core
+	// events are skipped either way.
+	Propagate func(event string) bool
+}
+
+type tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	propagate  func(event string) bool
+}
+
+// Instrument traces every emit and every listener on client.
+func Instrument(client mercury.MercuryClient, opts ...Options) {
+	client.Intercept(EmitInterceptor(opts...))
+	client.Use(ListenerMiddleware(opts...))
+}
+
+// EmitInterceptor starts a client span named after the event for every emit
+// and injects its context into the payload of events that propagate it.
+func EmitInterceptor(opts ...Options) mercury.EmitInterceptor {
+	t := newTracer(opts)
+
+	return func(next mercury.EmitHandler) mercury.EmitHandler {
+		return func(ctx context.Context, event string, targetAndPayload mercury.TargetAndPayload) (*mercury.AggregateResult, error) {
+			ctx, span := t.tracer.Start(ctx, event,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(EventKey.String(event)),
+			)
+			defer span.End()
+
+			if t.shouldPropagate(event) {
+				targetAndPayload = t.inject(ctx, targetAndPayload)
+			}
+
+			result, err := next(ctx, event, targetAndPayload)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return result, err
+			}
+
+			span.SetAttributes(
+				ResponsesKey.Int(result.TotalResponses),
+				ErrorsKey.Int(result.TotalErrors),
+			)
+
+			if err := result.Err(); err != nil {
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			return result, nil
+		}
+	}
+}
+
+// ListenerMiddleware starts a server span named after the event for every
+// listener, continuing the trace of the emit that triggered it. The trace
+// context is taken out of the payload before the listener sees it.
+func ListenerMiddleware(opts ...Options) mercury.ListenerMiddleware {
+	t := newTracer(opts)
+
+	return func(next mercury.ListenerHandler) mercury.ListenerHandler {
+		return func(ctx context.Context, event string, targetAndPayload mercury.TargetAndPayload) (any, error) {
+			var carrier propagation.MapCarrier
+			carrier, targetAndPayload = extract(targetAndPayload)
+			ctx = t.propagator.Extract(ctx, carrier)
+			ctx, span := t.tracer.Start(ctx, event,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(EventKey.String(event)),
+			)
+			defer span.End()
+
+			response, err := next(ctx, event, targetAndPayload)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			return response, err
+		}
+	}
+}
+
+func newTracer(opts []Options) *tracer {
+	var options Options
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	provider := options.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	propagator := options.Propagator
+	if propagator == nil {
+		propagator = propagation.TraceContext{}
+	}
+
+	return &tracer{
+		tracer:     provider.Tracer(tracerName),
+		propagator: propagator,
+		propagate:  options.Propagate,
+	}
+}
+
+func (t *tracer) shouldPropagate(event string) bool {
+	return t.propagate != nil && !isCoreEvent(event) && t.propagate(event)
+}
+
+// inject adds the trace context to a copy of the payload, so the caller's map
+// is left alone.
+func (t *tracer) inject(ctx context.Context, targetAndPayload mercury.TargetAndPayload) mercury.TargetAndPayload {
+	carrier := propagation.MapCarrier{}
+	t.propagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return targetAndPayload
+	}
+
+	payload := maps.Clone(targetAndPayload.Payload)
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	payload[TraceContextField] = map[string]string(carrier)
+	targetAndPayload.Payload = payload
+
+	return targetAndPayload
+}
+
+// extract takes the trace context out of the payload. It arrives as a map of
+// strings when emitted in process and as a map of any once decoded from JSON.
+func extract(targetAndPayload mercury.TargetAndPayload) (propagation.MapCarrier, mercury.TargetAndPayload) {
+	value, ok := targetAndPayload.Payload[TraceContextField]
+	if !ok {
+		return propagation.MapCarrier{}, targetAndPayload
+	}
+
+	carrier := propagation.MapCarrier{}
+	switch values := value.(type) {
+	case map[string]string:
+		maps.Copy(carrier, values)
+	case map[string]any:
+		for key, value := range values {
+			if value, ok := value.(string); ok {
+				carrier[key] = value
+			}
+		}
+	}
+
+	payload := maps.Clone(targetAndPayload.Payload)
+	delete(payload, TraceContextField)
+	if len(payload) == 0 {
+		payload = nil
+	}
+	targetAndPayload.Payload = payload
+
+	return carrier, targetAndPayload
+}
+
+// isCoreEvent reports whether event belongs to Mercury itself. Core events
+// have no namespace, like authenticate::v2020_12_25.
+func isCoreEvent(event string) bool {
+	name, _, _ := strings.Cut(event, "::")
+	return !strings.Contains(name, ".")
+}
@@ -0,0 +1,216 @@
+package otelmercury_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/otelmercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+
+	t.Run("emits become client spans with response counts", func(t *testing.T) {
+		exporter, client := makeTracedClient(t)
+
+		client.On("acme.traced-event::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"hello": "world"}
+		})
+		exporter.Reset()
+
+		_, err := client.Emit("acme.traced-event::v1")
+		require.NoError(t, err)
+
+		span := findSpan(t, exporter, "acme.traced-event::v1", trace.SpanKindClient)
+		require.Contains(t, span.Attributes, otelmercury.ResponsesKey.Int(1))
+		require.Contains(t, span.Attributes, otelmercury.ErrorsKey.Int(0))
+		require.Contains(t, span.Attributes, otelmercury.EventKey.String("acme.traced-event::v1"))
+	})
+
+	t.Run("listeners become server spans in the same trace as the emit", func(t *testing.T) {
+		exporter, client := makeTracedClient(t, otelmercury.Options{Propagate: func(string) bool { return true }})
+
+		client.On("acme.traced-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		exporter.Reset()
+
+		_, err := client.Emit("acme.traced-event::v1")
+		require.NoError(t, err)
+
+		emitSpan := findSpan(t, exporter, "acme.traced-event::v1", trace.SpanKindClient)
+		listenerSpan := findSpan(t, exporter, "acme.traced-event::v1", trace.SpanKindServer)
+
+		require.Equal(t, emitSpan.SpanContext.TraceID(), listenerSpan.SpanContext.TraceID())
+		require.Equal(t, emitSpan.SpanContext.SpanID(), listenerSpan.Parent.SpanID())
+		require.True(t, listenerSpan.Parent.IsRemote(), "Listener should continue the trace from the payload")
+	})
+
+	t.Run("carries trace context in the payload and hides it from listeners", func(t *testing.T) {
+		_, client := makeTracedClient(t, otelmercury.Options{Propagate: func(string) bool { return true }})
+		fake := testkit.LastFakeSocket()
+
+		var received mercury.TargetAndPayload
+		client.On("acme.traced-event::v1", func(targetAndPayload mercury.TargetAndPayload) any {
+			received = targetAndPayload
+			return nil
+		})
+		fake.ClearEmittedEvents()
+
+		payload := map[string]any{"message": "hey"}
+		_, err := client.Emit("acme.traced-event::v1", mercury.TargetAndPayload{Payload: payload})
+		require.NoError(t, err)
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Contains(t, emits[0].TargetAndPayload.Payload, otelmercury.TraceContextField)
+		require.Equal(t, map[string]any{"message": "hey"}, payload, "Caller's payload should be left alone")
+		require.Equal(t, map[string]any{"message": "hey"}, received.Payload)
+	})
+
+	t.Run("sends no trace context unless told to", func(t *testing.T) {
+		exporter, client := makeTracedClient(t)
+		fake := testkit.LastFakeSocket()
+
+		client.On("acme.traced-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		fake.ClearEmittedEvents()
+		exporter.Reset()
+
+		_, err := client.Emit("acme.traced-event::v1")
+		require.NoError(t, err)
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Nil(t, emits[0].TargetAndPayload.Payload)
+
+		listenerSpan := findSpan(t, exporter, "acme.traced-event::v1", trace.SpanKindServer)
+		require.False(t, listenerSpan.Parent.IsValid(), "Listener should start its own trace")
+	})
+
+	t.Run("passes validation of events that do not declare trace context", func(t *testing.T) {
+		testkit.BeforeEachInternal(t)
+		contract, err := mercury.ParseEventContract(testkit.GenerateWillSendVipEventSignature("acme"))
+		require.NoError(t, err)
+
+		_, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{Validator: mercury.NewValidator(contract)})
+		require.NoError(t, err)
+
+		provider := sdktrace.NewTracerProvider()
+		t.Cleanup(func() { _ = provider.Shutdown(t.Context()) })
+		otelmercury.Instrument(client, otelmercury.Options{TracerProvider: provider})
+
+		client.On("acme.will-send-vip::v1", func(mercury.TargetAndPayload) any {
+			return map[string]any{"messages": []string{"hey"}}
+		})
+
+		_, err = client.Emit("acme.will-send-vip::v1", mercury.TargetAndPayload{
+			Target:  map[string]any{"organizationId": "org-1"},
+			Payload: map[string]any{"message": "hey"},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("leaves core events untouched", func(t *testing.T) {
+		_, client := makeTracedClient(t, otelmercury.Options{Propagate: func(string) bool { return true }})
+		fake := testkit.LastFakeSocket()
+		fake.MakeEventReturnResponses("whoami::v2020_12_25", []mercury.ResponsePayload{{"type": "anonymous"}})
+		fake.ClearEmittedEvents()
+
+		_, err := client.Emit("whoami::v2020_12_25")
+		require.NoError(t, err)
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Nil(t, emits[0].TargetAndPayload.Payload)
+	})
+
+	t.Run("only propagates to the events it is told to", func(t *testing.T) {
+		_, client := makeTracedClient(t, otelmercury.Options{
+			Propagate: func(event string) bool { return event == "acme.traced-event::v1" },
+		})
+		fake := testkit.LastFakeSocket()
+
+		client.On("acme.traced-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		client.On("acme.untraced-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		fake.ClearEmittedEvents()
+
+		_, err := client.Emit("acme.traced-event::v1")
+		require.NoError(t, err)
+		_, err = client.Emit("acme.untraced-event::v1")
+		require.NoError(t, err)
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 2)
+		require.Contains(t, emits[0].TargetAndPayload.Payload, otelmercury.TraceContextField)
+		require.Nil(t, emits[1].TargetAndPayload.Payload)
+	})
+
+	t.Run("records listener errors on both spans", func(t *testing.T) {
+		exporter, client := makeTracedClient(t)
+
+		client.On("acme.failing-event::v1", func(mercury.TargetAndPayload) any {
+			return errors.New("not today")
+		})
+		exporter.Reset()
+
+		_, err := client.Emit("acme.failing-event::v1")
+		require.Error(t, err)
+
+		emitSpan := findSpan(t, exporter, "acme.failing-event::v1", trace.SpanKindClient)
+		require.Equal(t, codes.Error, emitSpan.Status.Code)
+		require.Contains(t, emitSpan.Attributes, otelmercury.ErrorsKey.Int(1))
+
+		listenerSpan := findSpan(t, exporter, "acme.failing-event::v1", trace.SpanKindServer)
+		require.Equal(t, codes.Error, listenerSpan.Status.Code)
+		require.Equal(t, "not today", listenerSpan.Status.Description)
+	})
+
+	t.Run("records emit failures", func(t *testing.T) {
+		exporter, client := makeTracedClient(t)
+
+		testkit.LastFakeSocket().MakeEventReturnError("acme.broken-event::v1", errors.New("socket closed"))
+		exporter.Reset()
+
+		_, err := client.Emit("acme.broken-event::v1")
+		require.Error(t, err)
+
+		span := findSpan(t, exporter, "acme.broken-event::v1", trace.SpanKindClient)
+		require.Equal(t, codes.Error, span.Status.Code)
+		require.Len(t, span.Events, 1, "Error should be recorded as an event")
+	})
+}
+
+func makeTracedClient(t *testing.T, opts ...otelmercury.Options) (*tracetest.InMemoryExporter, mercury.MercuryClient) {
+	testkit.BeforeEachInternal(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = provider.Shutdown(t.Context()) })
+
+	_, client, err := testkit.MakeFakeClient()
+	require.NoError(t, err)
+
+	var options otelmercury.Options
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	options.TracerProvider = provider
+	otelmercury.Instrument(client, options)
+
+	return exporter, client
+}
+
+func findSpan(t *testing.T, exporter *tracetest.InMemoryExporter, name string, kind trace.SpanKind) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range exporter.GetSpans() {
+		if span.Name == name && span.SpanKind == kind {
+			return span
+		}
+	}
+	require.Failf(t, "span not found", "no %s span named %s", kind, name)
+	return tracetest.SpanStub{}
+}
@@ -0,0 +1,142 @@
+// Package prommercury records Mercury client metrics with Prometheus.
+package prommercury
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+)
+
+const namespace = "mercury"
+
+var connectionStates = []mercury.ConnectionState{
+	mercury.ConnectionStateDisconnected,
+	mercury.ConnectionStateConnecting,
+	mercury.ConnectionStateConnected,
+	mercury.ConnectionStateReconnecting,
+	mercury.ConnectionStateReconnected,
+	mercury.ConnectionStateFailed,
+}
+
+var _ mercury.Metrics = (*Metrics)(nil)
+
+// Metrics implements mercury.Metrics with Prometheus collectors.
+type Metrics struct {
+	emits            *prometheus.CounterVec
+	emitDuration     *prometheus.HistogramVec
+	responderErrors  *prometheus.CounterVec
+	listenerCalls    *prometheus.CounterVec
+	listenerDuration *prometheus.HistogramVec
+	listenerPanics   *prometheus.CounterVec
+	reconnects       prometheus.Counter
+	connectionState  *prometheus.GaugeVec
+}
+
+// NewMetrics builds the collectors and registers them with registerer.
+func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		emits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "emits_total",
+			Help:      "Emits sent, by event and outcome.",
+		}, []string{"event", "outcome"}),
+		emitDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "emit_duration_seconds",
+			Help:      "Time from emit to the last response.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"event"}),
+		responderErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "responder_errors_total",
+			Help:      "Errors returned by responders, by event.",
+		}, []string{"event"}),
+		listenerCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "listener_invocations_total",
+			Help:      "Listener invocations, by event and outcome.",
+		}, []string{"event", "outcome"}),
+		listenerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "listener_duration_seconds",
+			Help:      "Time spent in listeners.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"event"}),
+		listenerPanics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "listener_panics_total",
+			Help:      "Listeners that panicked, by event.",
+		}, []string{"event"}),
+		reconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reconnects_total",
+			Help:      "Successful reconnects to Mercury.",
+		}),
+		connectionState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "connection_state",
+			Help:      "1 for the current connection state, 0 for every other state.",
+		}, []string{"state"}),
+	}
+
+	collectors := []prometheus.Collector{
+		m.emits,
+		m.emitDuration,
+		m.responderErrors,
+		m.listenerCalls,
+		m.listenerDuration,
+		m.listenerPanics,
+		m.reconnects,
+		m.connectionState,
+	}
+
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	m.ConnectionStateChanged(mercury.ConnectionStateDisconnected)
+
+	return m, nil
+}
+
+func (m *Metrics) EmitFinished(event string, duration time.Duration, result *mercury.AggregateResult, err error) {
+	m.emits.WithLabelValues(event, outcome(err)).Inc()
+	m.emitDuration.WithLabelValues(event).Observe(duration.Seconds())
+
+	if result != nil && result.TotalErrors > 0 {
+		m.responderErrors.WithLabelValues(event).Add(float64(result.TotalErrors))
+	}
+}
+
+func (m *Metrics) ListenerFinished(event string, duration time.Duration, err error) {
+	m.listenerCalls.WithLabelValues(event, outcome(err)).Inc()
+	m.listenerDuration.WithLabelValues(event).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ListenerPanicked(event string) {
+	m.listenerPanics.WithLabelValues(event).Inc()
+}
+
+func (m *Metrics) Reconnected() {
+	m.reconnects.Inc()
+}
+
+func (m *Metrics) ConnectionStateChanged(state mercury.ConnectionState) {
+	for _, candidate := range connectionStates {
+		value := 0.0
+		if candidate == state {
+			value = 1
+		}
+		m.connectionState.WithLabelValues(candidate.String()).Set(value)
+	}
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
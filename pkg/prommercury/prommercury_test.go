@@ -0,0 +1,140 @@
+package prommercury_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/prommercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+
+	t.Run("counts emits and responder errors per event", func(t *testing.T) {
+		registry, fake, client := makeMeteredClient(t)
+
+		client.On("measured-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		fake.MakeEventReturnError("broken-event::v1", errors.New("socket closed"))
+		client.On("failing-event::v1", func(mercury.TargetAndPayload) any { return errors.New("nope") })
+
+		_, err := client.Emit("measured-event::v1")
+		require.NoError(t, err)
+		_, err = client.Emit("measured-event::v1")
+		require.NoError(t, err)
+		_, err = client.Emit("broken-event::v1")
+		require.Error(t, err)
+		_, err = client.Emit("failing-event::v1")
+		require.Error(t, err)
+
+		require.Equal(t, 2.0, metricValue(t, registry, "mercury_emits_total", "measured-event::v1", "success"))
+		require.Equal(t, 1.0, metricValue(t, registry, "mercury_emits_total", "broken-event::v1", "error"))
+		require.Equal(t, 1.0, metricValue(t, registry, "mercury_responder_errors_total", "failing-event::v1"))
+		require.Equal(t, uint64(2), histogramCount(t, registry, "mercury_emit_duration_seconds", "measured-event::v1"))
+	})
+
+	t.Run("counts listener invocations and panics", func(t *testing.T) {
+		registry, _, client := makeMeteredClient(t)
+
+		client.On("measured-event::v1", func(mercury.TargetAndPayload) any { return nil })
+		client.On("panics::v1", func(mercury.TargetAndPayload) any { panic("kaboom") })
+
+		_, err := client.Emit("measured-event::v1")
+		require.NoError(t, err)
+		_, err = client.Emit("panics::v1")
+		require.Error(t, err)
+
+		require.Equal(t, 1.0, metricValue(t, registry, "mercury_listener_invocations_total", "measured-event::v1", "success"))
+		require.Equal(t, 1.0, metricValue(t, registry, "mercury_listener_invocations_total", "panics::v1", "error"))
+		require.Equal(t, 1.0, metricValue(t, registry, "mercury_listener_panics_total", "panics::v1"))
+		require.Equal(t, uint64(1), histogramCount(t, registry, "mercury_listener_duration_seconds", "measured-event::v1"))
+	})
+
+	t.Run("tracks reconnects and the connection state", func(t *testing.T) {
+		registry, fake, _ := makeMeteredClient(t)
+
+		require.Equal(t, 1.0, metricValue(t, registry, "mercury_connection_state", "connected"))
+		require.Equal(t, 0.0, metricValue(t, registry, "mercury_connection_state", "disconnected"))
+
+		fake.SimulateReconnect()
+
+		require.Equal(t, 1.0, metricValue(t, registry, "mercury_reconnects_total"))
+		require.Equal(t, 1.0, metricValue(t, registry, "mercury_connection_state", "reconnected"))
+		require.Equal(t, 0.0, metricValue(t, registry, "mercury_connection_state", "connected"))
+
+		fake.SimulateDisconnect()
+		require.Equal(t, 1.0, metricValue(t, registry, "mercury_connection_state", "disconnected"))
+	})
+
+	t.Run("fails to register twice with the same registry", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		_, err := prommercury.NewMetrics(registry)
+		require.NoError(t, err)
+
+		_, err = prommercury.NewMetrics(registry)
+		require.Error(t, err)
+	})
+}
+
+func makeMeteredClient(t *testing.T) (*prometheus.Registry, *testkit.FakeSocketClient, mercury.MercuryClient) {
+	testkit.BeforeEachInternal(t)
+
+	registry := prometheus.NewRegistry()
+	metrics, err := prommercury.NewMetrics(registry)
+	require.NoError(t, err)
+
+	fake, client, err := testkit.MakeFakeClient(mercury.MercuryClientOptions{Metrics: metrics})
+	require.NoError(t, err)
+
+	return registry, fake, client
+}
+
+func metricValue(t *testing.T, registry *prometheus.Registry, name string, labelValues ...string) float64 {
+	t.Helper()
+	metric := findMetric(t, registry, name, labelValues)
+	if metric.GetCounter() != nil {
+		return metric.GetCounter().GetValue()
+	}
+	return metric.GetGauge().GetValue()
+}
+
+func histogramCount(t *testing.T, registry *prometheus.Registry, name string, labelValues ...string) uint64 {
+	t.Helper()
+	return findMetric(t, registry, name, labelValues).GetHistogram().GetSampleCount()
+}
+
+func findMetric(t *testing.T, registry *prometheus.Registry, name string, labelValues []string) *dto.Metric {
+	t.Helper()
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if labelsMatch(metric, labelValues) {
+				return metric
+			}
+		}
+	}
+
+	require.Failf(t, "metric not found", "no %s with labels %v", name, labelValues)
+	return nil
+}
+
+func labelsMatch(metric *dto.Metric, labelValues []string) bool {
+	labels := metric.GetLabel()
+	if len(labels) != len(labelValues) {
+		return false
+	}
+	for i, label := range labels {
+		if label.GetValue() != labelValues[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,252 @@
+// Package skill runs a Spruce skill on top of the Mercury client. Declare the
+// skill's listeners with On, then Run connects, authenticates as the skill,
+// registers its event contract and listeners, and serves until the context is
+// done or the process gets SIGINT or SIGTERM.
+package skill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+)
+
+var (
+	ErrMissingCredentials = errors.New("skill id and api key are required")
+	ErrAlreadyRunning     = errors.New("skill is already running")
+)
+
+// Options configures a skill. Client is passed to mercury.NewMercuryClient,
+// so its Host, Logger and the rest apply to the skill's connection. Skills
+// always turn on Client.ShouldRetryConnect, so they reconnect, re-authenticate
+// and re-register their listeners after a dropped connection.
+type Options struct {
+	SkillId string
+	ApiKey  string
+	// Contract is sent to register-events as is every time the skill starts,
+	// so permission contracts and any other fields reach Mercury untouched.
+	// No events are registered when it is nil.
+	Contract map[string]any
+	Client   mercury.MercuryClientOptions
+	// ShutdownTimeout bounds how long Run waits for running listeners after
+	// it is asked to stop. Defaults to 10 seconds.
+	ShutdownTimeout time.Duration
+	// Signals stop the skill. Defaults to SIGINT and SIGTERM.
+	Signals []os.Signal
+}
+
+// Skill is a skill's runtime. Listeners must be added before Run is called,
+// and a Skill can only be run once.
+type Skill struct {
+	opts Options
+
+	mu         sync.Mutex
+	listeners  map[mercury.ListenerOptions]map[string]mercury.ContextListener
+	client     mercury.MercuryClient
+	isRunning  bool
+	isStopping bool
+	ready      chan struct{}
+	running    sync.WaitGroup
+}
+
+func New(opts Options) (*Skill, error) {
+	if opts.SkillId == "" || opts.ApiKey == "" {
+		return nil, ErrMissingCredentials
+	}
+
+	if opts.ShutdownTimeout <= 0 {
+		opts.ShutdownTimeout = 10 * time.Second
+	}
+
+	if len(opts.Signals) == 0 {
+		opts.Signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+
+	opts.Client.ShouldRetryConnect = true
+
+	return &Skill{
+		opts:      opts,
+		listeners: map[mercury.ListenerOptions]map[string]mercury.ContextListener{},
+		ready:     make(chan struct{}),
+	}, nil
+}
+
+// On declares a listener for a fully qualified event name. Listeners that
+// share options are registered with Mercury in a single emit when Run starts.
+func (s *Skill) On(fqen string, listener mercury.ContextListener, opts ...mercury.ListenerOptions) error {
+	var options mercury.ListenerOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return ErrAlreadyRunning
+	}
+
+	if s.listeners[options] == nil {
+		s.listeners[options] = map[string]mercury.ContextListener{}
+	}
+	s.listeners[options][fqen] = s.track(listener)
+
+	return nil
+}
+
+// Client returns the skill's connection once Run has connected, or nil.
+func (s *Skill) Client() mercury.MercuryClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client
+}
+
+// Ready is closed once the skill is authenticated and every listener is
+// registered.
+func (s *Skill) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Run starts the skill and blocks until ctx is done or one of the shutdown
+// signals arrives. It then stops listening, waits for running listeners and
+// disconnects. Run returns nil after a graceful shutdown and an error if the
+// skill could not start.
+func (s *Skill) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.isRunning {
+		s.mu.Unlock()
+		return ErrAlreadyRunning
+	}
+	s.isRunning = true
+	s.mu.Unlock()
+
+	ctx, stop := signal.NotifyContext(ctx, s.opts.Signals...)
+	defer stop()
+
+	client, err := mercury.NewMercuryClient(s.opts.Client)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Disconnect()
+
+	s.mu.Lock()
+	s.client = client
+	s.mu.Unlock()
+
+	client.OnSessionRestored(func(err error) {
+		if err != nil {
+			s.log().Error("Failed to restore skill session after reconnect", "error", err)
+		}
+	})
+
+	if err := s.start(ctx, client); err != nil {
+		return err
+	}
+
+	s.log().Info("Skill is running", "skillId", s.opts.SkillId)
+	close(s.ready)
+
+	<-ctx.Done()
+
+	return s.shutdown(client)
+}
+
+// start authenticates, syncs the contract and registers listeners, in the
+// order Mercury needs them.
+func (s *Skill) start(ctx context.Context, client mercury.MercuryClient) error {
+	if _, err := client.Authenticate(mercury.AuthenticatePayload{
+		SkillId: s.opts.SkillId,
+		ApiKey:  s.opts.ApiKey,
+	}); err != nil {
+		return fmt.Errorf("failed to authenticate as skill %s: %w", s.opts.SkillId, err)
+	}
+
+	if s.opts.Contract != nil {
+		if _, err := client.EmitContext(ctx, "register-events::v2020_12_25", mercury.TargetAndPayload{
+			Payload: map[string]any{
+				"contract": s.opts.Contract,
+			},
+		}); err != nil {
+			return fmt.Errorf("failed to register event contract: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for options, listeners := range s.listeners {
+		if _, err := client.OnManyContext(listeners, options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Skill) shutdown(client mercury.MercuryClient) error {
+	s.log().Info("Shutting down skill", "skillId", s.opts.SkillId)
+
+	s.mu.Lock()
+	s.isStopping = true
+	var events []string
+	for _, listeners := range s.listeners {
+		for fqen := range listeners {
+			events = append(events, fqen)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, event := range events {
+		if err := client.Off(event); err != nil {
+			s.log().Warn("Failed to unregister listener during shutdown", "event", event, "error", err)
+		}
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(s.opts.ShutdownTimeout):
+		s.log().Warn("Listeners still running at shutdown", "timeout", s.opts.ShutdownTimeout)
+	}
+
+	return nil
+}
+
+// track counts running listeners so shutdown can wait for them. Events that
+// arrive once shutdown has started are answered as busy.
+func (s *Skill) track(listener mercury.ContextListener) mercury.ContextListener {
+	return func(ctx context.Context, targetAndPayload mercury.TargetAndPayload) (any, error) {
+		s.mu.Lock()
+		if s.isStopping {
+			s.mu.Unlock()
+			return nil, &mercury.SpruceError{
+				Code:            mercury.ErrorCodeListenerBusy,
+				FriendlyMessage: "skill is shutting down",
+			}
+		}
+		s.running.Add(1)
+		s.mu.Unlock()
+
+		defer s.running.Done()
+		return listener(ctx, targetAndPayload)
+	}
+}
+
+func (s *Skill) log() *slog.Logger {
+	if s.opts.Client.Logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return s.opts.Client.Logger
+}
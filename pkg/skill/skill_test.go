@@ -0,0 +1,264 @@
+package skill_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/skill"
+	"github.com/sprucelabsai-community/mercury-client-go/pkg/testkit"
+	"github.com/stretchr/testify/require"
+	ioClient "github.com/zishang520/socket.io/clients/socket/v3"
+)
+
+func TestSkill(t *testing.T) {
+
+	t.Run("requires credentials", func(t *testing.T) {
+		_, err := skill.New(skill.Options{SkillId: "skill-1"})
+		require.ErrorIs(t, err, skill.ErrMissingCredentials)
+	})
+
+	t.Run("authenticates, registers its contract and listeners, then serves", func(t *testing.T) {
+		fake := fakeMercury(t)
+		contract := map[string]any{
+			"eventSignatures": map[string]any{
+				"will-send-vip::v1": map[string]any{"isGlobal": true},
+			},
+			"permissionContracts": []any{
+				map[string]any{"id": "acme-permissions", "name": "Acme", "permissions": []any{}},
+			},
+		}
+		s := makeSkill(t, skill.Options{Contract: contract})
+
+		require.NoError(t, s.On("acme.will-send-vip::v1", func(_ context.Context, targetAndPayload mercury.TargetAndPayload) (any, error) {
+			return map[string]any{"message": targetAndPayload.Payload["message"]}, nil
+		}))
+		require.NoError(t, s.On("acme.did-send-vip::v1", func(context.Context, mercury.TargetAndPayload) (any, error) {
+			return nil, nil
+		}))
+
+		cancel, done := runSkill(t, s)
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 3)
+		require.Equal(t, "authenticate::v2020_12_25", emits[0].Event)
+		require.Equal(t, "skill-1", emits[0].TargetAndPayload.Payload["skillId"])
+		require.Equal(t, "key-1", emits[0].TargetAndPayload.Payload["apiKey"])
+		require.Equal(t, "register-events::v2020_12_25", emits[1].Event)
+		require.Equal(t, contract, emits[1].TargetAndPayload.Payload["contract"])
+		require.Equal(t, "register-listeners::v2020_12_25", emits[2].Event)
+		require.Len(t, emits[2].TargetAndPayload.Payload["events"], 2)
+
+		responses, err := s.Client().Emit("acme.will-send-vip::v1", mercury.TargetAndPayload{
+			Payload: map[string]any{"message": "hey"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []mercury.ResponsePayload{{"message": "hey"}}, responses)
+
+		cancel()
+		require.NoError(t, receiveStop(t, done))
+	})
+
+	t.Run("restores its session after reconnecting", func(t *testing.T) {
+		fake := fakeMercury(t)
+		s := makeSkill(t, skill.Options{})
+		require.NoError(t, s.On("acme.will-send-vip::v1", func(context.Context, mercury.TargetAndPayload) (any, error) {
+			return nil, nil
+		}))
+		require.NoError(t, s.On("acme.did-send-vip::v1", func(context.Context, mercury.TargetAndPayload) (any, error) {
+			return nil, nil
+		}))
+
+		var socketOptions ioClient.OptionsInterface
+		mercury.SetConnect(func(host string, opts ioClient.OptionsInterface) (mercury.Socket, error) {
+			socketOptions = opts
+			return testkit.FakeSocketConnect(host, opts)
+		})
+
+		cancel, done := runSkill(t, s)
+		require.True(t, socketOptions.Reconnection(), "Skill should reconnect by default")
+
+		restored := make(chan error, 1)
+		s.Client().OnSessionRestored(func(err error) {
+			restored <- err
+		})
+		fake.ClearEmittedEvents()
+
+		fake.SimulateReconnect()
+
+		select {
+		case err := <-restored:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("Skill session was never restored")
+		}
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 2)
+		require.Equal(t, "authenticate::v2020_12_25", emits[0].Event)
+		require.Equal(t, "skill-1", emits[0].TargetAndPayload.Payload["skillId"])
+		require.Equal(t, "key-1", emits[0].TargetAndPayload.Payload["apiKey"])
+		require.Equal(t, "register-listeners::v2020_12_25", emits[1].Event)
+		require.Len(t, emits[1].TargetAndPayload.Payload["events"], 2)
+		require.Equal(t, mercury.ConnectionStateReconnected, s.Client().State())
+
+		cancel()
+		require.NoError(t, receiveStop(t, done))
+	})
+
+	t.Run("unregisters and disconnects when stopped", func(t *testing.T) {
+		fake := fakeMercury(t)
+		s := makeSkill(t, skill.Options{})
+		require.NoError(t, s.On("acme.will-send-vip::v1", func(context.Context, mercury.TargetAndPayload) (any, error) {
+			return nil, nil
+		}))
+
+		cancel, done := runSkill(t, s)
+		fake.ClearEmittedEvents()
+
+		cancel()
+		require.NoError(t, receiveStop(t, done))
+
+		emits := fake.EmittedEvents()
+		require.Len(t, emits, 1)
+		require.Equal(t, "unregister-listeners::v2020_12_25", emits[0].Event)
+		require.Equal(t, mercury.ConnectionStateDisconnected, s.Client().State())
+	})
+
+	t.Run("waits for running listeners before disconnecting", func(t *testing.T) {
+		fake := fakeMercury(t)
+		s := makeSkill(t, skill.Options{})
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		finished := make(chan struct{})
+		require.NoError(t, s.On("acme.slow::v1", func(context.Context, mercury.TargetAndPayload) (any, error) {
+			close(started)
+			<-release
+			close(finished)
+			return nil, nil
+		}, mercury.ListenerOptions{MaxConcurrent: 1, QueueSize: 1}))
+
+		cancel, done := runSkill(t, s)
+		fake.Trigger("acme.slow::v1")
+		<-started
+
+		cancel()
+		select {
+		case <-done:
+			t.Fatal("Run should wait for the running listener")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(release)
+		require.NoError(t, receiveStop(t, done))
+		<-finished
+	})
+
+	t.Run("stops on SIGTERM", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("SIGTERM cannot be sent on windows")
+		}
+
+		fakeMercury(t)
+		s := makeSkill(t, skill.Options{})
+		_, done := runSkill(t, s)
+
+		process, err := os.FindProcess(os.Getpid())
+		require.NoError(t, err)
+		require.NoError(t, process.Signal(syscall.SIGTERM))
+
+		require.NoError(t, receiveStop(t, done))
+	})
+
+	t.Run("fails to start when authentication fails", func(t *testing.T) {
+		fake := fakeMercury(t)
+		fake.MakeEventReturnResponses("authenticate::v2020_12_25", []mercury.ResponsePayload{
+			{"errors": []any{map[string]any{"code": "INVALID_AUTH"}}},
+		})
+
+		s := makeSkill(t, skill.Options{})
+		err := s.Run(context.Background())
+		require.ErrorContains(t, err, "failed to authenticate as skill skill-1")
+	})
+
+	t.Run("cannot add listeners or run again once running", func(t *testing.T) {
+		fakeMercury(t)
+		s := makeSkill(t, skill.Options{})
+		cancel, done := runSkill(t, s)
+
+		err := s.On("acme.late::v1", func(context.Context, mercury.TargetAndPayload) (any, error) { return nil, nil })
+		require.ErrorIs(t, err, skill.ErrAlreadyRunning)
+		require.ErrorIs(t, s.Run(context.Background()), skill.ErrAlreadyRunning)
+
+		cancel()
+		require.NoError(t, receiveStop(t, done))
+	})
+}
+
+// fakeMercury creates the fake socket the skill will connect to and makes it
+// accept the skill's credentials and contract.
+func fakeMercury(t *testing.T) *testkit.FakeSocketClient {
+	testkit.BeforeEach(t)
+	_, err := testkit.FakeSocketConnect("", nil)
+	require.NoError(t, err)
+
+	fake := testkit.LastFakeSocket()
+	fake.MakeEventReturnResponses("authenticate::v2020_12_25", []mercury.ResponsePayload{
+		{"auth": map[string]any{"skill": map[string]any{"id": "skill-1", "name": "Acme", "slug": "acme", "apiKey": "key-1"}}},
+	})
+	fake.MakeEventReturnResponses("register-events::v2020_12_25", []mercury.ResponsePayload{
+		{"fqens": []any{"acme.will-send-vip::v1"}},
+	})
+
+	return fake
+}
+
+func makeSkill(t *testing.T, opts skill.Options) *skill.Skill {
+	opts.SkillId = "skill-1"
+	opts.ApiKey = "key-1"
+	opts.Client.Host = "https://mercury.test"
+	opts.ShutdownTimeout = time.Second
+
+	s, err := skill.New(opts)
+	require.NoError(t, err)
+	return s
+}
+
+// runSkill runs s until the returned cancel is called or the test ends, and
+// waits for it to be ready.
+func runSkill(t *testing.T, s *skill.Skill) (context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+
+	select {
+	case <-s.Ready():
+	case err := <-done:
+		t.Fatalf("Skill stopped before it was ready: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the skill to be ready")
+	}
+
+	return cancel, done
+}
+
+func receiveStop(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the skill to stop")
+		return errors.New("timed out")
+	}
+}
@@ -2,6 +2,7 @@ package testkit
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/sprucelabsai-community/mercury-client-go/pkg/mercury"
@@ -9,15 +10,32 @@ import (
 	socketTypes "github.com/zishang520/socket.io/v3/pkg/types"
 )
 
+// FakeSocketClient stands in for the socket.io client in tests. It is safe
+// for concurrent use.
 type FakeSocketClient struct {
-	host         string
-	opts         ioClient.OptionsInterface
+	host string
+	opts ioClient.OptionsInterface
+
+	mu           sync.RWMutex
 	is_connected bool
 	listeners    []FakedListener
+	overrides    map[string]socketTypes.EventListener
+	failures     map[string]*fakeFailure
+
+	emitsMu sync.Mutex
+	emits   []FakeEmit
+}
+
+// FakeEmit is one event emitted through the fake socket.
+type FakeEmit struct {
+	Event            string
+	TargetAndPayload mercury.TargetAndPayload
 }
 
+// MakeEventReturnError makes every emit of event fail with err, no matter
+// which listeners are registered.
 func (s *FakeSocketClient) MakeEventReturnError(event string, err error) {
-	s.On(event, func(args ...any) {
+	s.override(event, func(args ...any) {
 		cb := PluckCallback(args)
 		if cb != nil {
 			cb(nil, err)
@@ -25,6 +43,42 @@ func (s *FakeSocketClient) MakeEventReturnError(event string, err error) {
 	})
 }
 
+// MakeEventReturnResponses makes the event answer as if one responder sent back
+// each payload. Payloads with an "errors" key are reported as responder errors.
+func (s *FakeSocketClient) MakeEventReturnResponses(event string, responses []mercury.ResponsePayload) {
+	s.override(event, func(args ...any) {
+		cb := PluckCallback(args)
+		if cb != nil {
+			cb([]any{BuildAggregateResponse(responses)}, nil)
+		}
+	})
+}
+
+// MakeEventFailTimes fails the next times emits of event with err before
+// letting them through. A nil err drops the ack instead, like a lost packet.
+func (s *FakeSocketClient) MakeEventFailTimes(event string, times int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures == nil {
+		s.failures = map[string]*fakeFailure{}
+	}
+	s.failures[mercury.ToSocketName(event)] = &fakeFailure{remaining: times, err: err}
+}
+
+type fakeFailure struct {
+	remaining int
+	err       error
+}
+
+func (s *FakeSocketClient) override(event string, listener socketTypes.EventListener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.overrides == nil {
+		s.overrides = map[string]socketTypes.EventListener{}
+	}
+	s.overrides[mercury.ToSocketName(event)] = listener
+}
+
 type FakedListener struct {
 	fqen string
 	cb   socketTypes.EventListener
@@ -38,85 +92,171 @@ var (
 )
 
 func FakeSocketConnect(host string, opts ioClient.OptionsInterface) (mercury.Socket, error) {
+	lastFakeSocketMu.Lock()
+	defer lastFakeSocketMu.Unlock()
+
 	if lastFakeSocket != nil {
 		return lastFakeSocket, nil
 	}
 
 	client := &FakeSocketClient{
-		host: host,
-		opts: opts,
+		host:         host,
+		opts:         opts,
+		is_connected: true,
 	}
 
-	client.is_connected = true
-	setLastFakeSocket(client)
+	lastFakeSocket = client
 
-	client.On("register-listeners::v2020_12_25", func(args ...any) {
+	acknowledge := func(args ...any) {
 		cb := PluckCallback(args)
 		if cb != nil {
 			cb([]any{}, nil)
 		}
-	})
+	}
+
+	client.On("register-listeners::v2020_12_25", acknowledge)
+	client.On("unregister-listeners::v2020_12_25", acknowledge)
 
 	return client, nil
 }
 
+// Emit calls every listener registered for event and answers with one
+// responder per listener, the way Mercury fans out to every skill.
 func (s *FakeSocketClient) Emit(event string, args ...any) error {
 	cb := PluckCallback(args)
+	s.recordEmit(event, args)
 
-	for _, listener := range s.listeners {
-		if listener.fqen == event {
-			argsWithBridge := args[:len(args)-1]
-			bridge := func(responseArgs []any, err error) {
-
-				if err != nil {
-					cb(nil, err)
-					return
-				}
-
-				var payload mercury.ResponsePayload
-				if len(responseArgs) > 0 {
-					payload, _ = responseArgs[0].(mercury.ResponsePayload)
-				}
-				mapped := BuildAggregateResponse([]mercury.ResponsePayload{payload})
-				if cb != nil {
-					cb([]any{mapped}, nil)
-				}
-			}
+	if failErr, failed := s.takeFailure(event); failed {
+		if cb != nil && failErr != nil {
+			cb(nil, failErr)
+		}
+		return nil
+	}
 
-			argsWithBridge = append(argsWithBridge, bridge)
-			listener.cb(argsWithBridge...)
+	s.mu.RLock()
+	override, hasOverride := s.overrides[event]
+	s.mu.RUnlock()
 
-			return nil
+	if hasOverride {
+		override(args...)
+		return nil
+	}
+
+	matched := s.listenersFor(event)
+	if len(matched) == 0 {
+		if cb != nil {
+			cb(nil, fmt.Errorf("no local listener for event %s.\n\nTry client, _ := mercury.NewMercuryClient()\nclient.On(\"%s\", func(targetAndPayload mercury.TargetAndPayload) any {\n\treturn nil\n})", event, event))
 		}
+		return nil
 	}
 
+	argsWithoutCallback := args
 	if cb != nil {
-		cb(nil, fmt.Errorf("no local listener for event %s.\n\nTry client, _ := mercury.NewMercuryClient()\nclient.On(\"%s\", func(targetAndPayload mercury.TargetAndPayload) any {\n\treturn nil\n})", event, event))
+		argsWithoutCallback = args[:len(args)-1]
+	}
+
+	collector := &responseCollector{
+		payloads: make([]mercury.ResponsePayload, len(matched)),
+		pending:  len(matched),
+		cb:       cb,
+	}
+
+	for i, listener := range matched {
+		listenerArgs := append(slices.Clone(argsWithoutCallback), collector.bridge(i))
+		listener.cb(listenerArgs...)
 	}
 
 	return nil
 }
 
+func (s *FakeSocketClient) takeFailure(event string) (error, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	failure, ok := s.failures[event]
+	if !ok || failure.remaining <= 0 {
+		return nil, false
+	}
+
+	failure.remaining--
+	return failure.err, true
+}
+
 func (s *FakeSocketClient) On(event string, listeners ...socketTypes.EventListener) error {
 	socketName := mercury.ToSocketName(event)
-	if len(listeners) > 0 {
-		var filteredListeners []FakedListener
-		for _, existing := range s.listeners {
-			if existing.fqen != socketName {
-				filteredListeners = append(filteredListeners, existing)
-			}
-		}
-		s.listeners = filteredListeners
-
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, listener := range listeners {
 		s.listeners = append(s.listeners, FakedListener{
 			fqen: socketName,
-			cb:   listeners[0],
+			cb:   listener,
 		})
 	}
 	return nil
 }
 
+func (s *FakeSocketClient) listenersFor(event string) []FakedListener {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var matched []FakedListener
+	for _, listener := range s.listeners {
+		if listener.fqen == event {
+			matched = append(matched, listener)
+		}
+	}
+	return matched
+}
+
+// responseCollector waits for every listener to ack before answering the
+// emitter with one aggregate response.
+type responseCollector struct {
+	mu       sync.Mutex
+	payloads []mercury.ResponsePayload
+	pending  int
+	done     bool
+	cb       SocketIoEmitCallback
+}
+
+func (c *responseCollector) bridge(index int) SocketIoEmitCallback {
+	return func(responseArgs []any, err error) {
+		c.mu.Lock()
+		if c.done {
+			c.mu.Unlock()
+			return
+		}
+
+		if err != nil {
+			c.done = true
+			c.mu.Unlock()
+			if c.cb != nil {
+				c.cb(nil, err)
+			}
+			return
+		}
+
+		if len(responseArgs) > 0 {
+			c.payloads[index], _ = responseArgs[0].(mercury.ResponsePayload)
+		}
+
+		c.pending--
+		if c.pending > 0 {
+			c.mu.Unlock()
+			return
+		}
+
+		c.done = true
+		mapped := BuildAggregateResponse(c.payloads)
+		c.mu.Unlock()
+
+		if c.cb != nil {
+			c.cb([]any{mapped}, nil)
+		}
+	}
+}
+
 func (s *FakeSocketClient) Connected() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.is_connected
 }
 
@@ -125,6 +265,8 @@ func (s *FakeSocketClient) Disconnect() mercury.Socket {
 }
 
 func (s *FakeSocketClient) SetConnected(connected bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.is_connected = connected
 }
 
@@ -136,8 +278,66 @@ func (s *FakeSocketClient) GetHost() string {
 	return s.host
 }
 
+// Off removes every listener for event, matching how SocketIoClient.Off
+// behaves.
 func (s *FakeSocketClient) Off(event string, listener socketTypes.EventListener) bool {
-	return false
+	socketName := mercury.ToSocketName(event)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := len(s.listeners)
+	s.listeners = slices.DeleteFunc(s.listeners, func(existing FakedListener) bool {
+		return existing.fqen == socketName
+	})
+	return len(s.listeners) != before
+}
+
+// EmittedEvents returns every event emitted through the fake, in order.
+func (s *FakeSocketClient) EmittedEvents() []FakeEmit {
+	s.emitsMu.Lock()
+	defer s.emitsMu.Unlock()
+	return append([]FakeEmit(nil), s.emits...)
+}
+
+// ClearEmittedEvents forgets every emit recorded so far.
+func (s *FakeSocketClient) ClearEmittedEvents() {
+	s.emitsMu.Lock()
+	defer s.emitsMu.Unlock()
+	s.emits = nil
+}
+
+func (s *FakeSocketClient) recordEmit(event string, args []any) {
+	emit := FakeEmit{Event: event}
+	if len(args) > 0 {
+		emit.TargetAndPayload, _ = args[0].(mercury.TargetAndPayload)
+	}
+
+	s.emitsMu.Lock()
+	defer s.emitsMu.Unlock()
+	s.emits = append(s.emits, emit)
+}
+
+// Trigger calls every listener registered for event, the same way the socket
+// does for lifecycle events like "connect" and "disconnect".
+func (s *FakeSocketClient) Trigger(event string, args ...any) {
+	for _, listener := range s.listenersFor(mercury.ToSocketName(event)) {
+		listener.cb(args...)
+	}
+}
+
+// SimulateDisconnect drops the connection the way a network blip would.
+func (s *FakeSocketClient) SimulateDisconnect() {
+	s.SetConnected(false)
+	s.Trigger("disconnect", "transport close")
+}
+
+// SimulateReconnect drops the connection and brings it back, firing the same
+// events the socket fires after a network blip.
+func (s *FakeSocketClient) SimulateReconnect() {
+	s.SimulateDisconnect()
+	s.Trigger("reconnect_attempt", 1)
+	s.SetConnected(true)
+	s.Trigger("reconnect", 1)
+	s.Trigger("connect")
 }
 
 func LastFakeSocket() *FakeSocketClient {
@@ -126,7 +126,7 @@ func TestFactory(t *testing.T) {
 		require.Error(t, err, "Emitting to unregistered event should return an error")
 	})
 
-	t.Run("only emits to the last listener set for an event", func(t *testing.T) {
+	t.Run("emits to every listener set for an event", func(t *testing.T) {
 		BeforeEach(t)
 
 		client, err := mercury.NewMercuryClient()
@@ -146,7 +146,7 @@ func TestFactory(t *testing.T) {
 
 		client.Emit("another.event::v100", mercury.TargetAndPayload{})
 
-		require.False(t, firstHit, "First listener should not have been hit")
+		require.True(t, firstHit, "First listener should have been hit")
 		require.True(t, secondHit, "Second listener should have been hit")
 	})
 
@@ -250,9 +250,11 @@ func TestFactory(t *testing.T) {
 }
 
 func emitAndAssertResponsePassedBack(t *testing.T, client mercury.MercuryClient, responsePayload mercury.ResponsePayload) {
-	client.On("map.response.event::v1", func(targetAndPayload mercury.TargetAndPayload) any {
+	subscription, err := client.On("map.response.event::v1", func(targetAndPayload mercury.TargetAndPayload) any {
 		return responsePayload
 	})
+	require.NoError(t, err)
+	defer subscription.Off()
 
 	responses, err := client.Emit("map.response.event::v1")
 	require.NoError(t, err, "Emitting to registered event should not return an error")
@@ -1,6 +1,7 @@
 package testkit
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -65,12 +66,12 @@ func MakeClientWithTestHost(t *testing.T, opts ...mercury.MercuryClientOptions)
 	host := os.Getenv("TEST_HOST")
 
 	require.NotEmpty(t, host, "TEST_HOST environment variable must be set for tests")
-	fmt.Println("Making client with test host " + host)
+	t.Logf("Making client with test host %s", host)
 
 	client, err := mercury.NewMercuryClient(append(opts, mercury.MercuryClientOptions{Host: host})...)
 	require.NoError(t, err, "Making Mercury client with test host should not return an error")
 
-	fmt.Println("Made client with test host: ", host)
+	t.Logf("Made client with test host: %s", host)
 
 	return client
 }
@@ -81,54 +82,36 @@ func GenerateRandomId() string {
 
 func EmitWhoAmI(t *testing.T, client mercury.MercuryClient) (*spruce.Person, string) {
 	t.Helper()
-	auth, err := client.Emit("whoami::v2020_12_25")
+	auth, err := client.WhoAmI(context.Background())
 	require.NoError(t, err, "Emit whoami should not return an error")
 	require.NotNil(t, auth, "Emit whoami should return a response")
-	require.Equal(t, 1, len(auth), "Emit whoami should return one response")
-	first := auth[0]
 
-	authMap := first["auth"].(map[string]any)
-
-	authType := first["type"].(string)
-	if authType == "anonymous" {
-		return nil, "anonymous"
+	if auth.Type == mercury.AuthTypeAnonymous {
+		return nil, string(mercury.AuthTypeAnonymous)
 	}
 
-	authPerson := authMap["person"].(map[string]any)
-	person, err := schemas.MakePerson(authPerson)
-	require.NoError(t, err, "Making person from whoami response should not return an error")
-	require.NotNil(t, person, "Person from whoami should not be nil")
+	require.NotNil(t, auth.Person, "Person from whoami should not be nil")
 
-	return person, authType
+	return auth.Person, string(auth.Type)
 }
 
 func Login(client mercury.MercuryClient, phone string) (*spruce.Person, string) {
-	requestPinResponse, _ := client.Emit("request-pin::v2020_12_25", mercury.TargetAndPayload{
-		Payload: map[string]any{
-			"phone": phone,
-		},
-	})
-	first := requestPinResponse[0]
-	challenge := first["challenge"].(string)
-
-	confirmPinResponse, _ := client.Emit("confirm-pin::v2020_12_25", mercury.TargetAndPayload{
-		Payload: map[string]any{
-			"challenge": challenge,
-			"pin":       "0000",
-		},
-	})
+	challenge, err := client.RequestPin(context.Background(), phone)
+	if err != nil {
+		return nil, ""
+	}
 
-	first = confirmPinResponse[0]
-	token := first["token"].(string)
-	personValues := first["person"].(map[string]any)
-	person, _ := schemas.MakePerson(personValues)
+	person, token, err := client.ConfirmPin(context.Background(), challenge, "0000")
+	if err != nil {
+		return nil, ""
+	}
 
 	return person, token
 }
 
 func LoginAsDemoPerson(t *testing.T, phone string) (mercury.MercuryClient, *spruce.Person, string) {
 	t.Helper()
-	fmt.Println("Logging in as demo person with phone:", phone)
+	t.Logf("Logging in as demo person with phone: %s", phone)
 	client := MakeClientWithTestHost(t)
 	person, token := Login(client, phone)
 	return client, person, token
@@ -195,7 +178,7 @@ func SeedRandomOrg(t *testing.T, client mercury.MercuryClient) *spruce.Organizat
 
 	require.NoError(t, err, "Seeding organization should not return an error")
 
-	fmt.Println("Create organization results:", results)
+	t.Logf("Create organization results: %v", results)
 	first := results[0]
 
 	orgValues, ok := first["organization"].(map[string]any)
@@ -307,7 +290,7 @@ func RegisterTestContract(t *testing.T, client mercury.MercuryClient) string {
 	require.NotNil(t, eventContract, "Expected event contract to be generated")
 
 	fqen := RegisterEvents(t, client, eventContract)
-	fmt.Println("Registered FQEN:", fqen)
+	t.Logf("Registered FQEN: %s", fqen)
 	return fqen
 }
 
@@ -327,7 +310,7 @@ func seedSkillInstallToOrgAndLoginAsSkill(t *testing.T, personClient mercury.Mer
 	t.Helper()
 	skill, err := SeedRandomSkill(personClient)
 	require.NoError(t, err, "Seeding skill should not return an error")
-	fmt.Println("Seeded skill:", skill)
+	t.Logf("Seeded skill: %v", skill)
 	err = InstallSkill(personClient, org.Id, skill.Id)
 	require.NoError(t, err, "Installing skill should not return an error")
 
@@ -340,18 +323,27 @@ func BuildAggregateResponse(responses []mercury.ResponsePayload) mercury.Mercury
 
 	responsesLen := len(responses)
 	allResponses := make([]mercury.MercurySingleResponse, responsesLen)
+	totalErrors := 0
 	for i, resp := range responses {
-		allResponses[i] = mercury.MercurySingleResponse{
+		single := mercury.MercurySingleResponse{
 			ResponderRef: fmt.Sprintf("fake-responder-%d", i+1),
 			Errors:       []any{},
 			Payload:      resp,
 		}
+
+		if errs, ok := resp["errors"].([]any); ok && len(errs) > 0 {
+			single.Errors = errs
+			single.Payload = nil
+			totalErrors++
+		}
+
+		allResponses[i] = single
 	}
 
 	return mercury.MercuryAggregateResponse{
 		TotalContracts: float64(responsesLen),
 		TotalResponses: float64(responsesLen),
-		TotalErrors:    0,
+		TotalErrors:    float64(totalErrors),
 		Responses:      allResponses,
 	}
 }